@@ -1,128 +1,515 @@
 package apiactor
 
 import (
+	"cmp"
 	"context"
-	"fmt"
+	"errors"
 	"io"
-	"math"
 	"net/http"
+	"slices"
+	"sync/atomic"
 	"time"
-
-	"github.com/jae2274/goutils/cchan"
 )
 
 type ApiActor struct {
-	rjChan   chan *requestJob
-	minDelay int64
+	rjChan       chan *requestJob
+	retryChan    chan *requestJob
+	wakeChan     chan struct{}
+	shutdownChan chan *shutdownRequest
+	stopped      chan struct{} // Shutdown이 시작되면 닫힌다.
+	exited       chan struct{} // run이 종료되면 닫힌다.
+	abortCtx     context.Context
+	abort        context.CancelCauseFunc
+
+	inFlight  atomic.Int64
+	completed atomic.Int64
+	abandoned atomic.Int64
+
+	client       *http.Client
+	limiter      Limiter
+	retryPolicy  RetryPolicy
+	aging        time.Duration
+	acceptStatus func(int) bool
+	cache        responseCache
+	breaker      *CircuitBreaker
+	hooks        hooks
+	propagation  propagation
+	dedup        *dedup
+	body         bodyOptions
+	session      *Session
 }
 
-func NewApiActor(ctx context.Context, minDelay int64) *ApiActor {
+// minDelay는 밀리초 단위이며, WithLimiter 옵션으로 Limiter를 지정한 경우에는 무시된다.
+func NewApiActor(ctx context.Context, minDelay int64, opts ...Option) *ApiActor {
+	o := newOptions(opts)
+
+	limiter := o.limiter
+	if limiter == nil {
+		limiter = NewDelayLimiter(time.Millisecond * time.Duration(minDelay))
+	}
+
+	client := o.httpClient()
+	if o.session != nil && o.session.Jar != nil {
+		withJar := *client
+		withJar.Jar = o.session.Jar
+		client = &withJar
+	}
+
+	abortCtx, abort := context.WithCancelCause(context.Background())
 	apiActor := &ApiActor{
-		rjChan:   make(chan *requestJob),
-		minDelay: minDelay,
+		rjChan:       make(chan *requestJob),
+		retryChan:    make(chan *requestJob),
+		wakeChan:     make(chan struct{}, 1),
+		shutdownChan: make(chan *shutdownRequest),
+		stopped:      make(chan struct{}),
+		exited:       make(chan struct{}),
+		abortCtx:     abortCtx,
+		abort:        abort,
+		client:       client,
+		limiter:      limiter,
+		retryPolicy:  o.retryPolicy,
+		aging:        o.aging,
+		acceptStatus: o.acceptStatus,
+		cache:        responseCache{o.cache, o.session},
+		breaker:      o.breaker,
+		hooks:        o.hooks,
+		propagation:  o.propagation,
+		dedup:        o.dedup,
+		body:         o.body,
+		session:      o.session,
 	}
 	go run(ctx, apiActor)
 
 	return apiActor
 }
 
-type Request struct {
-	Method string
-	Url    string
-	Header http.Header
-}
+type requestJob struct {
+	ctx        context.Context
+	request    *Request
+	resultChan chan<- *result
 
-func NewRequest(method string, url string) *Request {
-	r := &Request{
-		Method: method,
-		Url:    url,
-		Header: make(http.Header),
-	}
+	seq        uint64
+	enqueuedAt time.Time
+	attempt    int
+	notBefore  time.Time
+	probe      bool // CircuitBreaker가 half-open 상태에서 허용한 요청인지 여부
 
-	return r
+	queuedAt  time.Time // 이번 시도에서 대기열에 들어온 시각
+	startedAt time.Time
 }
 
-type requestJob struct {
-	request    *http.Request
-	resultChan chan<- *result
+// effectivePriority는 대기한 시간만큼 Priority를 올려, 낮은 Priority의 요청이 무한히 밀리지 않도록 한다.
+func (rj *requestJob) effectivePriority(now time.Time, aging time.Duration) int {
+	if aging <= 0 {
+		return rj.request.Priority
+	}
+	return rj.request.Priority + int(now.Sub(rj.enqueuedAt)/aging)
 }
 
 type result struct {
-	reader io.ReadCloser
-	err    error
+	res *Response
+	err error
+}
+
+// Response는 CallResponse가 반환하는 응답이며, Body는 호출자가 닫아야 한다.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
 }
 
 func run(ctx context.Context, a *ApiActor) {
-	var lastEndedTime int64 = math.MaxInt64
+	defer close(a.exited)
+	defer a.abort(ErrActorStopped)
+
+	pending := make([]*requestJob, 0)
+	var seq uint64
+	var shutdown *shutdownRequest
 
 	for {
-		timeDiff := time.Now().UnixMilli() - lastEndedTime
+		var nextWake time.Duration
+		pending, nextWake = a.dispatch(pending)
 
-		if timeDiff < a.minDelay && timeDiff > 0 {
-			time.Sleep(time.Millisecond * time.Duration(a.minDelay-timeDiff))
+		if shutdown != nil && len(pending) == 0 && a.inFlight.Load() == 0 {
+			shutdown.finish(a, nil)
+			return
 		}
 
-		lastEndedTime = time.Now().UnixMilli()
+		timer := time.NewTimer(nextWake)
+		if nextWake <= 0 {
+			timer.Stop()
+		}
 
-		rj, ok := cchan.Receive(ctx, a.rjChan)
-		if !ok {
+		select {
+		case <-ctx.Done():
+			a.reject(pending) //대기하고 있을 다른 goroutine을 위해 종료를 알린다.
 			for {
 				select {
-				case reqJob := <-a.rjChan:
-					close((*reqJob).resultChan) //대기하고 있을 다른 goroutine을 위해 resultChan을 닫아준다.
+				case rj := <-a.rjChan:
+					a.hooks.enqueue(rj)
+					a.reject([]*requestJob{rj})
 				default:
+					if shutdown != nil {
+						shutdown.finish(a, ErrActorStopped)
+					}
 					return
 				}
 			}
+		case rj := <-a.rjChan:
+			a.hooks.enqueue(rj)
+			if shutdown != nil { // Shutdown이 시작된 이후의 요청은 받지 않는다.
+				a.reject([]*requestJob{rj})
+				break
+			}
+			seq++
+			rj.seq = seq
+			rj.enqueuedAt = time.Now()
+			pending = append(pending, rj)
+		case rj := <-a.retryChan: // 재시도로 다시 들어온 요청은 처음 들어온 순서를 유지한다.
+			a.hooks.enqueue(rj)
+			pending = append(pending, rj)
+		case req := <-a.shutdownChan:
+			if shutdown != nil { // 동시에 호출된 Shutdown 중 하나만 종료를 진행한다.
+				req.resultChan <- shutdownResult{err: ErrActorStopped}
+				break
+			}
+			shutdown = req
+			shutdown.start(a)
+		case <-shutdown.deadline():
+			// 기한 내에 끝나지 않은 요청들은 모두 ErrActorStopped로 거절하며, 실행중인 요청이 모두 끝날 때까지 기다린다.
+			shutdown.expired = true
+			a.reject(pending)
+			pending = pending[:0]
+			a.abort(ErrActorStopped)
+		case <-a.wakeChan:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func (a *ApiActor) reject(rjs []*requestJob) {
+	for _, rj := range rjs {
+		a.hooks.finish(rj, 0, ErrActorStopped)
+		rj.resultChan <- &result{err: ErrActorStopped}
+	}
+	a.abandoned.Add(int64(len(rjs)))
+}
+
+// dispatch는 Limiter가 허용하는 요청들을 실행하고, 남은 요청들과 다음 dispatch까지의 최대 대기시간을 반환한다.
+// 대기시간이 0이면 새로운 요청이 들어오거나 실행중인 요청이 끝날 때까지 기다린다.
+func (a *ApiActor) dispatch(pending []*requestJob) ([]*requestJob, time.Duration) {
+	var nextWake time.Duration
+	setNextWake := func(wait time.Duration) {
+		if wait > 0 && (nextWake == 0 || wait < nextWake) {
+			nextWake = wait
 		}
+	}
 
-		rc, err := CallApi((*rj).request)
+	now := time.Now()
+	slices.SortFunc(pending, func(rj1, rj2 *requestJob) int {
+		if p1, p2 := rj1.effectivePriority(now, a.aging), rj2.effectivePriority(now, a.aging); p1 != p2 {
+			return cmp.Compare(p2, p1)
+		}
+		return cmp.Compare(rj1.seq, rj2.seq)
+	})
 
-		ok = cchan.Send(
-			ctx,
-			(*rj).resultChan,
-			&result{
-				reader: rc,
-				err:    err,
-			},
-		)
+	remained := pending[:0]
+	for _, rj := range pending {
+		if rj.ctx.Err() != nil { // 호출자가 취소한 요청은 대기열에서 제거한다.
+			err := callerCancelledError(rj.ctx)
+			a.hooks.finish(rj, 0, err)
+			rj.resultChan <- &result{err: err}
+			continue
+		}
+
+		if wait := time.Until(rj.notBefore); wait > 0 {
+			setNextWake(wait)
+			remained = append(remained, rj)
+			continue
+		}
+
+		host := hostOf(rj.request)
+		probe, err := a.breaker.allow(host)
+		if err != nil { // circuit이 열려있으면 요청을 보내지 않고 바로 실패시킨다.
+			a.completed.Add(1)
+			a.hooks.finish(rj, 0, err)
+			rj.resultChan <- &result{err: err}
+			continue
+		}
+
+		release, wait, ok := a.limiter.Reserve(rj.request)
 		if !ok {
-			close((*rj).resultChan)
-			return
+			if probe {
+				a.breaker.cancelProbe(host)
+			}
+			setNextWake(wait)
+			remained = append(remained, rj)
+			continue
 		}
+
+		rj.probe = probe
+		a.hooks.start(rj)
+		a.inFlight.Add(1)
+		go a.execute(rj, release)
 	}
+
+	return remained, nextWake
 }
 
-func (a *ApiActor) Call(r *Request) (io.ReadCloser, error) {
+func (a *ApiActor) execute(rj *requestJob, release func()) {
+	defer a.wake()
+	defer a.inFlight.Add(-1)
 
-	httpReq, err := converthttpReq(r)
+	// Shutdown의 기한이 지나면 실행중인 요청도 취소된다.
+	execCtx, cancel := context.WithCancelCause(rj.ctx)
+	stopAbort := context.AfterFunc(a.abortCtx, func() { cancel(ErrActorStopped) })
+
+	res, statusCode, err := a.callOnce(execCtx, rj.request, release)
+	aborted := !stopAbort() && context.Cause(execCtx) == ErrActorStopped
+	a.recordCircuit(rj, err, aborted)
+	if aborted {
+		err = ErrActorStopped
+	}
+	a.hooks.finish(rj, statusCode, err)
 	if err != nil {
-		return nil, err
+		cancel(nil)
+		if res != nil {
+			res.Body.Close()
+		}
+		res = nil
+	} else {
+		res.Body = &cancelOnClose{res.Body, cancel} // body를 모두 읽기 전에 요청의 context가 취소되지 않도록 한다.
 	}
 
-	resultChan := make(chan *result)
-	a.rjChan <- &requestJob{
-		request:    httpReq,
-		resultChan: resultChan,
+	if aborted {
+		a.abandoned.Add(1)
+		rj.resultChan <- &result{err: err}
+		return
+	}
+
+	var reqErr error
+	if err != nil {
+		reqErr = &RequestError{Method: rj.request.Method, Url: rj.request.Url, Err: err, Idempotent: rj.request.isIdempotent()}
+	}
+
+	if err != nil && !isBuildError(err) && a.retryPolicy != nil {
+		rj.attempt++
+		if delay, retry := a.retryPolicy.NextDelay(rj.attempt, reqErr); retry {
+			// 재시도 또한 대기열을 거치므로 Limiter의 제한을 위반하지 않는다.
+			rj.notBefore = time.Now().Add(delay)
+			select {
+			case a.retryChan <- rj:
+				return
+			case <-a.exited:
+			}
+		}
 	}
 
-	result, ok := <-resultChan
-	if !ok {
-		return nil, fmt.Errorf("resultChan closed")
+	if err != nil {
+		if rj.ctx.Err() != nil {
+			err = callerCancelledError(rj.ctx)
+		} else {
+			err = reqErr
+		}
 	}
 
-	close(resultChan)
-	return result.reader, result.err
+	a.completed.Add(1)
+	rj.resultChan <- &result{
+		res: res,
+		err: err,
+	}
 }
 
-func converthttpReq(r *Request) (*http.Request, error) {
-	httpReq, err := http.NewRequest(r.Method, r.Url, nil)
+// recordCircuit은 요청의 결과를 CircuitBreaker에 기록한다. 요청을 보내지 못했거나 호출자가 취소한 경우는 기록하지 않는다.
+func (a *ApiActor) recordCircuit(rj *requestJob, err error, aborted bool) {
+	if a.breaker == nil {
+		return
+	}
+
+	host := hostOf(rj.request)
+	if aborted || (err != nil && rj.ctx.Err() != nil) || isBuildError(err) {
+		if rj.probe {
+			a.breaker.cancelProbe(host)
+		}
+		return
+	}
+
+	a.breaker.record(host, err)
+}
 
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelCauseFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel(nil)
+	return c.ReadCloser.Close()
+}
+
+func (a *ApiActor) wake() {
+	select {
+	case a.wakeChan <- struct{}{}:
+	default:
+	}
+}
+
+// callOnce는 요청을 한번 보내고, 응답을 받았다면 status code를 함께 반환한다.
+func (a *ApiActor) callOnce(ctx context.Context, r *Request, release func()) (*Response, int, error) {
+	defer a.wake()
+	defer release()
+
+	res, cached, err := a.send(ctx, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	acceptStatus := r.AcceptStatus
+	if acceptStatus == nil {
+		acceptStatus = a.acceptStatus
+	}
+
+	if cached != nil && res.StatusCode == http.StatusNotModified {
+		return a.cache.notModified(r, cached, res), res.StatusCode, nil
+	}
+
+	rc, err := GetBodyWith(res, nil, acceptStatus)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+
+	rc, err = a.body.wrap(res, rc)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+
+	rc, err = a.cache.store(r, res, rc)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: rc}, res.StatusCode, nil
+}
+
+// send는 r을 http.Request로 변환하여 보내며, 재검증을 위해 저장된 응답이 있다면 함께 반환한다.
+// Session의 Authenticator가 있고 401 응답을 받았다면, 인증 정보를 갱신하여 한번 더 보낸다.
+func (a *ApiActor) send(ctx context.Context, r *Request) (*http.Response, *CachedResponse, error) {
+	for refreshed := false; ; refreshed = true {
+		httpReq, err := converthttpReq(ctx, r)
+		if err != nil {
+			return nil, nil, &buildError{err}
+		}
+		a.propagation.apply(httpReq)
+		a.body.prepare(httpReq)
+
+		generation, err := a.session.prepare(ctx, httpReq)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cached := a.cache.revalidate(r, httpReq)
+
+		res, err := a.client.Do(httpReq)
+		if err != nil {
+			return nil, nil, err
+		}
+		if observer, ok := a.limiter.(ResponseObserver); ok {
+			observer.Observe(r, res)
+		}
+
+		if res.StatusCode != http.StatusUnauthorized || refreshed || !a.session.canRefresh() {
+			return res, cached, nil
+		}
+
+		res.Body.Close()
+		if err := a.session.refresh(ctx, generation); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// buildError는 요청을 생성하는 과정에서 발생한 에러로, 재시도하더라도 같은 결과이므로 재시도하지 않는다.
+type buildError struct {
+	err error
+}
+
+func (e *buildError) Error() string {
+	return e.err.Error()
+}
+
+func (e *buildError) Unwrap() error {
+	return e.err
+}
+
+func isBuildError(err error) bool {
+	var bErr *buildError
+	return errors.As(err, &bErr)
+}
+
+func (a *ApiActor) Call(r *Request) (io.ReadCloser, error) {
+	return a.CallContext(context.Background(), r)
+}
+
+// CallContext는 ctx가 종료되면 대기열에서 요청을 제거하며, 이미 실행중인 요청은 ctx를 통해 취소된다.
+// 반환되는 에러는 ErrActorStopped, ErrCallerCancelled, *CircuitOpenError 혹은 *RequestError이다.
+func (a *ApiActor) CallContext(ctx context.Context, r *Request) (io.ReadCloser, error) {
+	res, err := a.CallResponse(ctx, r)
 	if err != nil {
 		return nil, err
 	}
+	return res.Body, nil
+}
+
+// CallResponse는 CallContext와 같으며, body와 함께 응답의 status code와 header를 반환한다.
+func (a *ApiActor) CallResponse(ctx context.Context, r *Request) (*Response, error) {
+	if res, ok := a.cache.fresh(r); ok {
+		return res, nil
+	}
+
+	ctx = a.propagation.context(ctx)
+	if key, ok := a.dedup.key(r); ok {
+		return a.dedup.do(ctx, key, r, a.call)
+	}
+	return a.call(ctx, r)
+}
+
+// call은 요청을 대기열에 넣고 결과를 기다린다.
+func (a *ApiActor) call(ctx context.Context, r *Request) (*Response, error) {
+	resultChan := make(chan *result, 1)
+	rj := &requestJob{
+		ctx:        ctx,
+		request:    r,
+		resultChan: resultChan,
+	}
+
+	select {
+	case <-a.stopped:
+		return nil, ErrActorStopped
+	default:
+	}
 
-	httpReq.Header = r.Header
+	select {
+	case a.rjChan <- rj:
+	case <-a.stopped:
+		return nil, ErrActorStopped
+	case <-a.exited:
+		return nil, ErrActorStopped
+	case <-ctx.Done():
+		return nil, callerCancelledError(ctx)
+	}
+
+	stopWake := context.AfterFunc(ctx, a.wake)
+	defer stopWake()
 
-	return httpReq, nil
+	select {
+	case result := <-resultChan:
+		return result.res, result.err
+	case <-ctx.Done():
+		go func() { // 뒤늦게 도착한 응답의 body를 닫아준다.
+			if result := <-resultChan; result.res != nil {
+				result.res.Body.Close()
+			}
+		}()
+		return nil, callerCancelledError(ctx)
+	}
 }
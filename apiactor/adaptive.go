@@ -0,0 +1,149 @@
+package apiactor
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ResponseObserver는 응답에 따라 제한을 조정하는 Limiter가 구현한다.
+// ApiActor는 Limiter가 ResponseObserver를 구현하면 응답을 받을 때마다, release 함수를 호출하기 전에 Observe를 호출한다.
+type ResponseObserver interface {
+	Observe(r *Request, res *http.Response)
+}
+
+// AdaptiveLimiter는 DelayLimiter처럼 한번에 하나의 요청만 실행하지만, 응답에 따라 요청 사이의 delay를 조정한다.
+// 429, 503 응답을 받거나 X-RateLimit-Remaining이 LowRemaining 이하이면 delay에 Backoff를 곱하고,
+// SuccessThreshold번 연속으로 제한되지 않은 응답을 받으면 delay에서 RecoverStep을 뺀다.
+// Retry-After 헤더가 있거나 X-RateLimit-Remaining이 0이면 Retry-After, X-RateLimit-Reset이 가리키는 시각까지 요청을 보내지 않는다.
+type AdaptiveLimiter struct {
+	MinDelay         time.Duration
+	MaxDelay         time.Duration
+	Backoff          float64
+	RecoverStep      time.Duration
+	SuccessThreshold int
+	LowRemaining     int
+
+	mu           sync.Mutex
+	delay        time.Duration
+	running      bool
+	lastEnded    time.Time
+	blockedUntil time.Time
+	successes    int
+}
+
+// NewAdaptiveLimiter는 minDelay부터 시작하며, delay를 2배씩 늘리고 10번 연속으로 성공하면 (maxDelay-minDelay)/10만큼 줄인다.
+func NewAdaptiveLimiter(minDelay, maxDelay time.Duration) *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		MinDelay:         minDelay,
+		MaxDelay:         maxDelay,
+		Backoff:          2,
+		RecoverStep:      max((maxDelay-minDelay)/10, time.Millisecond),
+		SuccessThreshold: 10,
+		delay:            minDelay,
+	}
+}
+
+func (l *AdaptiveLimiter) Reserve(r *Request) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running {
+		return nil, 0, false
+	}
+
+	readyAt := l.lastEnded.Add(l.delay)
+	if l.blockedUntil.After(readyAt) {
+		readyAt = l.blockedUntil
+	}
+	if wait := time.Until(readyAt); wait > 0 {
+		return nil, wait, false
+	}
+
+	l.running = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.running = false
+		l.lastEnded = time.Now()
+	}, 0, true
+}
+
+func (l *AdaptiveLimiter) Observe(r *Request, res *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	throttled := res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable
+
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		l.blockUntil(now.Add(retryAfter))
+	}
+
+	if remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining")); err == nil {
+		if remaining <= l.LowRemaining {
+			throttled = true
+		}
+		if reset, ok := parseRateLimitReset(res.Header.Get("X-RateLimit-Reset"), now); ok && remaining <= 0 {
+			l.blockUntil(reset)
+		}
+	}
+
+	if throttled {
+		l.successes = 0
+		l.delay = min(max(time.Duration(float64(l.delay)*l.Backoff), l.RecoverStep), l.MaxDelay)
+		return
+	}
+
+	l.successes++
+	if l.successes >= l.SuccessThreshold {
+		l.successes = 0
+		l.delay = max(l.delay-l.RecoverStep, l.MinDelay)
+	}
+}
+
+func (l *AdaptiveLimiter) blockUntil(t time.Time) {
+	if t.After(l.blockedUntil) {
+		l.blockedUntil = t
+	}
+}
+
+// Delay는 현재 요청 사이의 delay이다.
+func (l *AdaptiveLimiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.delay
+}
+
+// Rate는 현재 delay로 계산한 초당 최대 요청 수이며, delay가 0이면 0을 반환한다.
+func (l *AdaptiveLimiter) Rate() float64 {
+	delay := l.Delay()
+	if delay <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(delay)
+}
+
+// BlockedUntil은 Retry-After, X-RateLimit-Reset에 의해 요청을 보내지 않는 시각이다.
+func (l *AdaptiveLimiter) BlockedUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.blockedUntil
+}
+
+// X-RateLimit-Reset 헤더는 API마다 unix time 혹은 남은 초를 사용하므로, 값의 크기로 구분한다.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+
+	if seconds > 1_000_000_000 {
+		return time.Unix(seconds, 0), true
+	}
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
@@ -0,0 +1,90 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(statusCode int, header map[string]string) *http.Response {
+	res := &http.Response{StatusCode: statusCode, Header: make(http.Header)}
+	for key, value := range header {
+		res.Header.Set(key, value)
+	}
+	return res
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	req := apiactor.NewRequest("GET", "http://example.com")
+
+	t.Run("429 응답을 받으면 delay를 늘리고, 연속으로 성공하면 delay를 줄인다.", func(t *testing.T) {
+		limiter := apiactor.NewAdaptiveLimiter(100*time.Millisecond, time.Second)
+		require.Equal(t, 100*time.Millisecond, limiter.Delay())
+		require.Equal(t, float64(10), limiter.Rate())
+
+		limiter.Observe(req, newResponse(http.StatusTooManyRequests, nil))
+		limiter.Observe(req, newResponse(http.StatusServiceUnavailable, nil))
+		require.Equal(t, 400*time.Millisecond, limiter.Delay())
+
+		for i := 0; i < 10; i++ {
+			limiter.Observe(req, newResponse(http.StatusOK, nil))
+		}
+		require.Equal(t, 310*time.Millisecond, limiter.Delay())
+
+		for i := 0; i < 10; i++ {
+			limiter.Observe(req, newResponse(http.StatusTooManyRequests, nil))
+		}
+		require.Equal(t, time.Second, limiter.Delay())
+	})
+
+	t.Run("X-RateLimit-Remaining이 LowRemaining 이하이면 delay를 늘린다.", func(t *testing.T) {
+		limiter := apiactor.NewAdaptiveLimiter(100*time.Millisecond, time.Second)
+		limiter.LowRemaining = 5
+
+		limiter.Observe(req, newResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "6"}))
+		require.Equal(t, 100*time.Millisecond, limiter.Delay())
+
+		limiter.Observe(req, newResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "5"}))
+		require.Equal(t, 200*time.Millisecond, limiter.Delay())
+	})
+
+	t.Run("Retry-After와 X-RateLimit-Reset이 가리키는 시각까지 요청을 보내지 않는다.", func(t *testing.T) {
+		limiter := apiactor.NewAdaptiveLimiter(0, time.Second)
+
+		limiter.Observe(req, newResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "2"}))
+		require.WithinDuration(t, time.Now().Add(2*time.Second), limiter.BlockedUntil(), 100*time.Millisecond)
+
+		reset := time.Now().Add(time.Minute).Truncate(time.Second)
+		limiter.Observe(req, newResponse(http.StatusOK, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+		}))
+		require.Equal(t, reset, limiter.BlockedUntil())
+
+		_, wait, ok := limiter.Reserve(req)
+		require.False(t, ok)
+		require.Greater(t, wait, 50*time.Second)
+	})
+
+	t.Run("ApiActor는 응답을 받을 때마다 AdaptiveLimiter에 전달한다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(1, http.StatusTooManyRequests, nil))
+		limiter := apiactor.NewAdaptiveLimiter(0, time.Second)
+		limiter.RecoverStep = 100 * time.Millisecond
+		apiActor := apiactor.NewApiActor(context.Background(), 0,
+			apiactor.WithLimiter(apiactor.NewHostLimiter(func(host string) apiactor.Limiter { return limiter })),
+			apiactor.WithRetryPolicy(&apiactor.BackoffRetryPolicy{MaxAttempts: 2, RetryableStatusCodes: apiactor.DefaultRetryableStatusCodes}),
+		)
+
+		start := time.Now()
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), called.Load())
+		require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		require.Equal(t, 100*time.Millisecond, limiter.Delay())
+	})
+}
@@ -1,49 +1,144 @@
 package apiactor
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 )
 
-func CallApi(httpReq *http.Request) (io.ReadCloser, error) {
-	client := &http.Client{}
+// CallApi에는 WithHttpClient, WithTransport, WithAcceptStatus, WithGenerateTraceId, WithCtxHeader,
+// WithMaxBodySize, WithDecompression, WithCharsetConversion 옵션만 적용된다.
+// httpReq를 복사하여 context의 값을 header로 설정하므로, httpReq는 변경되지 않는다.
+func CallApi(httpReq *http.Request, opts ...Option) (io.ReadCloser, error) {
+	o := newOptions(opts)
+
+	httpReq = httpReq.Clone(o.propagation.context(httpReq.Context()))
+	o.propagation.apply(httpReq)
+	o.body.prepare(httpReq)
+
+	return callApi(o.httpClient(), httpReq, o.acceptStatus, o.body)
+}
+
+func callApi(client *http.Client, httpReq *http.Request, acceptStatus func(int) bool, body bodyOptions) (io.ReadCloser, error) {
 	res, err := client.Do(httpReq)
 
-	return GetBody(res, err)
+	rc, err := GetBodyWith(res, err, acceptStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return body.wrap(res, rc)
+}
+
+// IsSuccessStatus는 2xx status code만 허용한다.
+func IsSuccessStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// AcceptStatus는 주어진 status code들만 허용하는 함수를 반환한다.
+func AcceptStatus(statusCodes ...int) func(int) bool {
+	return func(statusCode int) bool {
+		return slices.Contains(statusCodes, statusCode)
+	}
 }
 
 func GetBody(res *http.Response, err error) (io.ReadCloser, error) {
+	return GetBodyWith(res, err, IsSuccessStatus)
+}
+
+// GetBodyWith는 acceptStatus가 허용하지 않는 status code의 응답을 HttpError로 반환한다. acceptStatus가 nil이면 IsSuccessStatus를 사용한다.
+// 204 No Content와 같이 body가 없는 응답은 http.NoBody를 반환한다.
+func GetBodyWith(res *http.Response, err error, acceptStatus func(int) bool) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, &HttpError{res.StatusCode, res.Status}
+	if acceptStatus == nil {
+		acceptStatus = IsSuccessStatus
+	}
+
+	if !acceptStatus(res.StatusCode) {
+		return nil, newHttpError(res)
 	}
 
 	if res.Body == nil {
-		return nil, fmt.Errorf("response body is nil")
+		return http.NoBody, nil
 	}
 
 	return res.Body, nil
 }
 
+// MaxErrorBodySize는 HttpError에 저장되는 응답 body의 최대 크기이다.
+var MaxErrorBodySize int64 = 4 * 1024
+
 type HttpError struct {
 	StatusCode int
 	Status     string
+	Header     http.Header
+	Body       []byte // 최대 MaxErrorBodySize만큼의 응답 body
+	Method     string
+	Url        string
+}
+
+func newHttpError(res *http.Response) *HttpError {
+	httpErr := &HttpError{StatusCode: res.StatusCode, Status: res.Status, Header: res.Header}
+
+	if res.Request != nil {
+		httpErr.Method = res.Request.Method
+		httpErr.Url = res.Request.URL.String()
+	}
+
+	if res.Body != nil {
+		defer res.Body.Close()
+		httpErr.Body, _ = io.ReadAll(io.LimitReader(res.Body, MaxErrorBodySize))
+	}
+
+	return httpErr
 }
 
 func (h *HttpError) Error() string {
-	return fmt.Sprintf("HttpError: %s", h.Status)
+	if h.Method == "" {
+		return fmt.Sprintf("HttpError: %s", h.Status)
+	}
+	return fmt.Sprintf("HttpError: %s (%s %s)", h.Status, h.Method, h.Url)
+}
+
+func (h *HttpError) IsClientError() bool {
+	return h.StatusCode >= 400 && h.StatusCode < 500
+}
+
+func (h *HttpError) IsServerError() bool {
+	return h.StatusCode >= 500 && h.StatusCode < 600
+}
+
+// IsRetryable은 StatusCode가 DefaultRetryableStatusCodes에 포함되는지 여부를 반환한다.
+func (h *HttpError) IsRetryable() bool {
+	return slices.Contains(DefaultRetryableStatusCodes, h.StatusCode)
 }
 
 func IsHttpError(err error) bool {
-	_, ok := err.(*HttpError)
-	return ok
+	var httpErr *HttpError
+	return errors.As(err, &httpErr)
 }
 
 func IsHttpErrorWithStatusCode(err error, statusCode int) bool {
-	httpErr, ok := err.(*HttpError)
-	return ok && httpErr.StatusCode == statusCode
+	var httpErr *HttpError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == statusCode
+}
+
+func IsClientError(err error) bool {
+	var httpErr *HttpError
+	return errors.As(err, &httpErr) && httpErr.IsClientError()
+}
+
+func IsServerError(err error) bool {
+	var httpErr *HttpError
+	return errors.As(err, &httpErr) && httpErr.IsServerError()
+}
+
+func IsRetryable(err error) bool {
+	var httpErr *HttpError
+	return errors.As(err, &httpErr) && httpErr.IsRetryable()
 }
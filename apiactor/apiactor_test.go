@@ -1,8 +1,11 @@
 package apiactor_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"testing"
 	"time"
 
@@ -63,3 +66,180 @@ func TestIsHttpError(t *testing.T) {
 	errInterface = fmt.Errorf("error")
 	require.False(t, apiactor.IsHttpError(errInterface))
 }
+
+func TestCallContext(t *testing.T) {
+	t.Run("호출자의 context가 종료되면 대기열의 요청은 실행되지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(0, 0, nil))
+		slowServer, _ := newTestServer(t, slowHandler(300*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		go apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", server.URL))
+		require.ErrorIs(t, err, apiactor.ErrCallerCancelled)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 200*time.Millisecond)
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+		require.NoError(t, err)
+		require.Equal(t, int32(0), called.Load())
+	})
+
+	t.Run("실행중인 요청도 호출자의 context가 종료되면 취소된다.", func(t *testing.T) {
+		slowServer, _ := newTestServer(t, slowHandler(300*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", slowServer.URL))
+		require.ErrorIs(t, err, apiactor.ErrCallerCancelled)
+		require.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("ApiActor가 종료되면 대기중인 요청과 이후의 요청은 ErrActorStopped를 반환한다.", func(t *testing.T) {
+		slowServer, _ := newTestServer(t, slowHandler(300*time.Millisecond, nil))
+		ctx, cancel := context.WithCancel(context.Background())
+		apiActor := apiactor.NewApiActor(ctx, 0)
+
+		go apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+		time.Sleep(50 * time.Millisecond)
+
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+			errChan <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		cancel()
+		require.ErrorIs(t, <-errChan, apiactor.ErrActorStopped)
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+		require.ErrorIs(t, err, apiactor.ErrActorStopped)
+	})
+
+	t.Run("요청이 실패하면 RequestError를 반환한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, flakyHandler(1, http.StatusNotFound, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		_, err := apiActor.CallContext(context.Background(), apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsRequestError(err))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusNotFound))
+		require.NotErrorIs(t, err, apiactor.ErrCallerCancelled)
+		require.NotErrorIs(t, err, apiactor.ErrActorStopped)
+	})
+}
+
+func TestHttpErrorDetail(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		switch r.URL.Path {
+		case "/bad-request":
+			w.Header().Set("X-Error-Code", "INVALID_KEYWORD")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"keyword is required"}`))
+		case "/large":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write(bytes.Repeat([]byte("a"), int(apiactor.MaxErrorBodySize)*2))
+		}
+	})
+
+	apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+	t.Run("HttpError는 응답 body, header와 요청 정보를 포함한다.", func(t *testing.T) {
+		_, err := apiActor.Call(apiactor.NewRequest("POST", server.URL+"/bad-request"))
+
+		var httpErr *apiactor.HttpError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+		require.Equal(t, `{"message":"keyword is required"}`, string(httpErr.Body))
+		require.Equal(t, "INVALID_KEYWORD", httpErr.Header.Get("X-Error-Code"))
+		require.Equal(t, "POST", httpErr.Method)
+		require.Equal(t, server.URL+"/bad-request", httpErr.Url)
+
+		require.True(t, apiactor.IsClientError(err))
+		require.False(t, apiactor.IsServerError(err))
+		require.False(t, apiactor.IsRetryable(err))
+	})
+
+	t.Run("HttpError의 body는 MaxErrorBodySize까지만 저장된다.", func(t *testing.T) {
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/large"))
+
+		var httpErr *apiactor.HttpError
+		require.ErrorAs(t, err, &httpErr)
+		require.Len(t, httpErr.Body, int(apiactor.MaxErrorBodySize))
+
+		require.False(t, apiactor.IsClientError(err))
+		require.True(t, apiactor.IsServerError(err))
+		require.True(t, apiactor.IsRetryable(err))
+	})
+
+	t.Run("HttpError가 아닌 에러는 false를 반환한다.", func(t *testing.T) {
+		err := fmt.Errorf("error")
+		require.False(t, apiactor.IsClientError(err))
+		require.False(t, apiactor.IsServerError(err))
+		require.False(t, apiactor.IsRetryable(err))
+	})
+}
+
+func TestAcceptStatus(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("created"))
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		}
+	})
+
+	t.Run("기본적으로 2xx 응답은 성공으로 처리된다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		rc, err := apiActor.Call(apiactor.NewRequest("POST", server.URL+"/created"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "created", string(body))
+
+		rc, err = apiActor.Call(apiactor.NewRequest("DELETE", server.URL+"/no-content"))
+		require.NoError(t, err)
+		body, err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Empty(t, body)
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL+"/not-modified"))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusNotModified))
+	})
+
+	t.Run("ApiActor에 지정한 AcceptStatus로 성공 여부를 판단한다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithAcceptStatus(apiactor.AcceptStatus(http.StatusOK, http.StatusNotModified)))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/not-modified"))
+		require.NoError(t, err)
+
+		_, err = apiActor.Call(apiactor.NewRequest("POST", server.URL+"/created"))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusCreated))
+	})
+
+	t.Run("Request에 지정한 AcceptStatus가 ApiActor의 AcceptStatus보다 우선한다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithAcceptStatus(apiactor.AcceptStatus(http.StatusOK)))
+
+		_, err := apiActor.Call(apiactor.NewRequest("POST", server.URL+"/created").SetAcceptStatus(apiactor.AcceptStatus(http.StatusCreated)))
+		require.NoError(t, err)
+	})
+
+	t.Run("body가 없는 응답은 http.NoBody를 반환한다.", func(t *testing.T) {
+		rc, err := apiactor.GetBody(&http.Response{StatusCode: http.StatusNoContent, Status: "204 No Content"}, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.NoBody, rc)
+	})
+}
@@ -0,0 +1,224 @@
+package apiactor
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrBodyTooLarge는 응답 body가 WithMaxBodySize로 지정한 크기를 넘었을 때 반환되는 에러와 errors.Is로 비교할 수 있다.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+type BodyTooLargeError struct {
+	Limit  int64
+	Method string
+	Url    string
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("%s %s: response body exceeds %d bytes", e.Method, e.Url, e.Limit)
+}
+
+func (e *BodyTooLargeError) Is(target error) bool {
+	return target == ErrBodyTooLarge
+}
+
+// Decoder는 Content-Encoding으로 압축된 body를 해제하는 reader를 반환한다.
+type Decoder func(r io.Reader) (io.ReadCloser, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"gzip":    func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		"deflate": newDeflateReader,
+		"br":      func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(brotli.NewReader(r)), nil },
+	}
+)
+
+// RegisterDecoder는 WithDecompression에서 사용할 Content-Encoding의 Decoder를 등록한다.
+// gzip, deflate, br은 기본으로 등록되어 있으며, 같은 Content-Encoding을 등록하면 기존의 Decoder를 대체한다.
+func RegisterDecoder(encoding string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[strings.ToLower(encoding)] = decoder
+}
+
+// UnregisterDecoder는 등록된 Content-Encoding의 Decoder를 제거하여, 더 이상 Accept-Encoding 헤더로 보내지 않는다.
+func UnregisterDecoder(encoding string) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	delete(decoders, strings.ToLower(encoding))
+}
+
+func lookupDecoder(encoding string) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	decoder, ok := decoders[strings.ToLower(strings.TrimSpace(encoding))]
+	return decoder, ok
+}
+
+// acceptEncoding은 등록된 Decoder들의 Content-Encoding을 Accept-Encoding 헤더의 값으로 반환한다.
+func acceptEncoding() string {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	encodings := make([]string, 0, len(decoders))
+	for encoding := range decoders {
+		encodings = append(encodings, encoding)
+	}
+	slices.Sort(encodings)
+	return strings.Join(encodings, ", ")
+}
+
+// HTTP의 deflate는 zlib 형식이지만, zlib header 없이 deflate 데이터만 보내는 서버도 있다.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// bodyOptions는 응답 body를 호출자에게 전달하기 전에 적용할 처리들이다.
+type bodyOptions struct {
+	maxSize    int64 // 0 이하이면 제한하지 않는다.
+	decompress bool
+	charset    bool
+}
+
+// prepare는 요청에 Accept-Encoding 헤더를 설정한다. 이미 설정된 헤더는 덮어쓰지 않는다.
+func (o bodyOptions) prepare(httpReq *http.Request) {
+	if o.decompress && httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", acceptEncoding())
+	}
+}
+
+// wrap은 body의 압축을 해제하고, 크기를 제한하고, UTF-8로 변환하는 reader를 반환한다.
+// body를 변환하면 응답 헤더의 Content-Encoding, Content-Length, Content-Type도 함께 변경한다.
+func (o bodyOptions) wrap(res *http.Response, body io.ReadCloser) (io.ReadCloser, error) {
+	if body == http.NoBody {
+		return body, nil
+	}
+
+	var tooLarge *BodyTooLargeError
+	if o.maxSize > 0 {
+		tooLarge = &BodyTooLargeError{Limit: o.maxSize}
+		if res.Request != nil {
+			tooLarge.Method, tooLarge.Url = res.Request.Method, res.Request.URL.String()
+		}
+		if res.ContentLength > o.maxSize && res.Header.Get("Content-Encoding") == "" {
+			body.Close()
+			return nil, tooLarge
+		}
+	}
+
+	reader := &multiCloser{Reader: body, closers: []io.Closer{body}}
+
+	if encoding := res.Header.Get("Content-Encoding"); o.decompress && encoding != "" {
+		decoder, ok := lookupDecoder(encoding)
+		if ok {
+			decoded, err := decoder(reader.Reader)
+			if err != nil {
+				body.Close()
+				return nil, err
+			}
+			reader.Reader = decoded
+			reader.closers = append(reader.closers, decoded)
+
+			res.Header.Del("Content-Encoding")
+			res.Header.Del("Content-Length")
+			res.ContentLength = -1
+			res.Uncompressed = true
+		}
+	}
+
+	if tooLarge != nil { // 압축이 해제된 크기를 제한하여 압축 폭탄으로부터 decoder를 보호한다.
+		reader.Reader = &limitedReader{r: reader.Reader, remained: o.maxSize, err: tooLarge}
+	}
+
+	if contentType := res.Header.Get("Content-Type"); o.charset && isTextContent(contentType) {
+		converted, err := charset.NewReader(reader.Reader, contentType)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		reader.Reader = converted
+		res.Header.Set("Content-Type", withUtf8Charset(contentType))
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+	}
+
+	return reader, nil
+}
+
+func isTextContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") || mediaType == "application/xml"
+}
+
+func withUtf8Charset(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	params["charset"] = "utf-8"
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// limitedReader는 remained 바이트를 넘게 읽으려 하면 err를 반환한다.
+type limitedReader struct {
+	r        io.Reader
+	remained int64
+	err      error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remained < 0 {
+		return 0, l.err
+	}
+
+	// 제한을 넘었는지 확인하기 위해 1바이트를 더 읽는다.
+	if int64(len(p)) > l.remained+1 {
+		p = p[:l.remained+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.remained {
+		n = int(l.remained)
+		l.remained = -1
+		return n, l.err
+	}
+	l.remained -= int64(n)
+	return n, err
+}
+
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	var errs []error
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		errs = append(errs, m.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,154 @@
+package apiactor_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/korean"
+)
+
+const posting = "<html><body>백엔드 개발자 채용</body></html>"
+
+func encodingHandler(w http.ResponseWriter, r *http.Request, count int32) {
+	switch r.URL.Path {
+	case "/large":
+		w.Write(bytes.Repeat([]byte("a"), 100))
+	case "/large-chunked":
+		w.Write(bytes.Repeat([]byte("a"), 8))
+		w.(http.Flusher).Flush()
+		w.Write(bytes.Repeat([]byte("a"), 92))
+	case "/gzip", "/zlib", "/deflate", "/br":
+		encoding := strings.TrimPrefix(r.URL.Path, "/")
+		var compressed bytes.Buffer
+		var writer io.WriteCloser
+		switch encoding {
+		case "gzip":
+			writer = gzip.NewWriter(&compressed)
+		case "zlib":
+			encoding = "deflate"
+			writer = zlib.NewWriter(&compressed)
+		case "deflate":
+			writer, _ = flate.NewWriter(&compressed, flate.DefaultCompression)
+		case "br":
+			writer = brotli.NewWriter(&compressed)
+		}
+		writer.Write([]byte(strings.Repeat("listing", 100)))
+		writer.Close()
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(compressed.Bytes())
+	case "/euc-kr":
+		encoded, _ := korean.EUCKR.NewEncoder().String(posting)
+		w.Header().Set("Content-Type", "text/html; charset=euc-kr")
+		w.Write([]byte(encoded))
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	server, called := newTestServer(t, encodingHandler)
+	apiActor := apiactor.NewApiActor(context.Background(), 0,
+		apiactor.WithMaxBodySize(10),
+		apiactor.WithRetryPolicy(&apiactor.BackoffRetryPolicy{MaxAttempts: 3}),
+	)
+
+	t.Run("Content-Length가 제한을 넘으면 재시도하지 않고 *BodyTooLargeError를 반환한다.", func(t *testing.T) {
+		called.Store(0)
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/large"))
+		require.ErrorIs(t, err, apiactor.ErrBodyTooLarge)
+		require.Equal(t, int32(1), called.Load())
+
+		var tooLarge *apiactor.BodyTooLargeError
+		require.ErrorAs(t, err, &tooLarge)
+		require.Equal(t, int64(10), tooLarge.Limit)
+		require.Equal(t, server.URL+"/large", tooLarge.Url)
+	})
+
+	t.Run("크기를 알 수 없는 body는 읽는 도중 제한을 넘으면 *BodyTooLargeError를 반환한다.", func(t *testing.T) {
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/large-chunked"))
+		require.NoError(t, err)
+		defer rc.Close()
+
+		body, err := io.ReadAll(rc)
+		require.ErrorIs(t, err, apiactor.ErrBodyTooLarge)
+		require.Len(t, body, 10)
+	})
+}
+
+func TestDecompression(t *testing.T) {
+	server, _ := newTestServer(t, encodingHandler)
+	apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDecompression())
+
+	for _, path := range []string{"/gzip", "/zlib", "/deflate", "/br"} {
+		t.Run(path+" 응답의 압축을 해제한다.", func(t *testing.T) {
+			res, err := apiActor.CallResponse(context.Background(), apiactor.NewRequest("GET", server.URL+path))
+			require.NoError(t, err)
+			require.Equal(t, strings.Repeat("listing", 100), readAll(t, res.Body))
+			require.Empty(t, res.Header.Get("Content-Encoding"))
+		})
+	}
+
+	t.Run("압축이 해제된 크기를 제한한다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDecompression(), apiactor.WithMaxBodySize(100))
+
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/gzip"))
+		require.NoError(t, err)
+		defer rc.Close()
+
+		_, err = io.ReadAll(rc)
+		require.ErrorIs(t, err, apiactor.ErrBodyTooLarge)
+	})
+
+	t.Run("RegisterDecoder로 등록한 Content-Encoding도 해제한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+			require.Contains(t, r.Header.Get("Accept-Encoding"), "x-upper")
+			w.Header().Set("Content-Encoding", "x-upper")
+			w.Write([]byte("LISTING"))
+		})
+
+		apiactor.RegisterDecoder("x-upper", func(r io.Reader) (io.ReadCloser, error) {
+			body, err := io.ReadAll(r)
+			return io.NopCloser(strings.NewReader(strings.ToLower(string(body)))), err
+		})
+		t.Cleanup(func() { apiactor.UnregisterDecoder("x-upper") }) // 다른 테스트의 Accept-Encoding에 영향을 주지 않는다.
+
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, "listing", readAll(t, rc))
+	})
+}
+
+func TestCharsetConversion(t *testing.T) {
+	server, _ := newTestServer(t, encodingHandler)
+
+	t.Run("Content-Type의 charset에서 UTF-8로 변환한다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCharsetConversion())
+
+		res, err := apiActor.CallResponse(context.Background(), apiactor.NewRequest("GET", server.URL+"/euc-kr"))
+		require.NoError(t, err)
+		require.Equal(t, posting, readAll(t, res.Body))
+		require.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+	})
+
+	t.Run("CallApi에도 적용된다.", func(t *testing.T) {
+		httpReq, err := http.NewRequest("GET", server.URL+"/euc-kr", nil)
+		require.NoError(t, err)
+
+		rc, err := apiactor.CallApi(httpReq, apiactor.WithCharsetConversion())
+		require.NoError(t, err)
+		require.Equal(t, posting, readAll(t, rc))
+	})
+}
@@ -0,0 +1,323 @@
+package apiactor
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+	// RequestHeader는 응답의 Vary 헤더에 지정된 요청 헤더들의 값으로, 이 값들이 같은 요청에만 저장된 응답을 사용한다.
+	RequestHeader http.Header
+}
+
+func (c *CachedResponse) reader() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(c.Body))
+}
+
+func (c *CachedResponse) response() *Response {
+	return &Response{StatusCode: c.StatusCode, Header: c.Header.Clone(), Body: c.reader()}
+}
+
+// isFresh는 Cache-Control의 max-age가 지나지 않아 서버에 재검증하지 않고 사용할 수 있는지 여부를 반환한다.
+func (c *CachedResponse) isFresh(now time.Time) bool {
+	directives := parseCacheControl(c.Header.Get("Cache-Control"))
+	if _, ok := directives["no-cache"]; ok {
+		return false
+	}
+
+	maxAge, err := strconv.Atoi(directives["max-age"])
+	if err != nil {
+		return false
+	}
+
+	return now.Before(c.StoredAt.Add(time.Duration(maxAge) * time.Second))
+}
+
+func (c *CachedResponse) canRevalidate() bool {
+	return c.Header.Get("ETag") != "" || c.Header.Get("Last-Modified") != ""
+}
+
+func (c *CachedResponse) matches(r *Request) bool {
+	for key, values := range c.RequestHeader {
+		if !slices.Equal(r.Header.Values(key), values) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cache는 ApiActor가 응답을 저장하는 저장소이다. 여러 goroutine에서 동시에 호출될 수 있다.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, res *CachedResponse)
+	Delete(key string)
+}
+
+type lruEntry struct {
+	key string
+	res *CachedResponse
+}
+
+// LRUCache는 최대 capacity개의 응답을 저장하며, 가득 차면 가장 오랫동안 사용되지 않은 응답을 제거한다.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*lruEntry).res, true
+}
+
+func (c *LRUCache) Set(key string, res *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry).res = res
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key, res})
+	for c.capacity > 0 && c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// responseCache는 ApiActor에서 Cache를 사용하는 방법을 정의한다. cache가 nil이면 아무것도 하지 않는다.
+// body가 없는 GET 요청만 캐시되며, 여러 ApiActor가 Cache를 공유할 수 있으므로 Cache-Control이 private인 응답은 저장하지 않는다.
+// Session의 cookie와 인증 정보는 요청을 보낼 때 추가되므로, session의 응답은 다른 Session의 요청에 사용되지 않도록 key에 session을 포함한다.
+type responseCache struct {
+	cache   Cache
+	session *Session
+}
+
+func (c responseCache) key(r *Request) (string, bool) {
+	if !strings.EqualFold(r.Method, http.MethodGet) || r.Body != nil {
+		return "", false
+	}
+
+	fullUrl, err := r.fullUrl()
+	if err != nil {
+		return "", false
+	}
+
+	return http.MethodGet + " " + fullUrl + credentialKey(r.Header) + c.session.key(), true
+}
+
+// credentialHeaders는 요청한 사용자를 구분하는 헤더들로, 다른 사용자의 응답을 받지 않도록 캐시와 중복 제거의 key에 항상 포함된다.
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+// credentialKey는 credentialHeaders의 값들을 key에 추가할 문자열로 반환한다. 인증 정보가 key에 그대로 남지 않도록 hash를 사용한다.
+func credentialKey(header http.Header) string {
+	hash := sha256.New()
+	found := false
+	for _, key := range credentialHeaders {
+		for _, value := range header.Values(key) {
+			found = true
+			io.WriteString(hash, key+": "+value+"\n")
+		}
+	}
+
+	if !found {
+		return ""
+	}
+	return "\ncredential: " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// varyHeader는 응답의 Vary 헤더에 지정된 요청 헤더들의 값을 반환한다. Vary가 *이면 어떤 요청에도 사용할 수 없으므로 false를 반환한다.
+func varyHeader(r *Request, header http.Header) (http.Header, bool) {
+	var vary http.Header
+	for _, value := range header.Values("Vary") {
+		for _, key := range strings.Split(value, ",") {
+			key = strings.TrimSpace(key)
+			if key == "*" {
+				return nil, false
+			}
+			if key == "" {
+				continue
+			}
+
+			if vary == nil {
+				vary = make(http.Header)
+			}
+			vary[http.CanonicalHeaderKey(key)] = slices.Clone(r.Header.Values(key))
+		}
+	}
+	return vary, true
+}
+
+// fresh는 재검증 없이 사용할 수 있는 응답이 있으면 반환한다. 이 경우 요청은 대기열을 거치지 않으므로 Limiter의 제한에 포함되지 않는다.
+func (c responseCache) fresh(r *Request) (*Response, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+
+	key, ok := c.key(r)
+	if !ok {
+		return nil, false
+	}
+
+	cached, ok := c.cache.Get(key)
+	if !ok || !cached.matches(r) || !cached.isFresh(time.Now()) {
+		return nil, false
+	}
+
+	return cached.response(), true
+}
+
+// revalidate는 저장된 응답이 있으면 If-None-Match, If-Modified-Since 헤더를 설정하고 저장된 응답을 반환한다.
+func (c responseCache) revalidate(r *Request, httpReq *http.Request) *CachedResponse {
+	if c.cache == nil {
+		return nil
+	}
+
+	key, ok := c.key(r)
+	if !ok {
+		return nil
+	}
+
+	cached, ok := c.cache.Get(key)
+	if !ok || !cached.matches(r) || !cached.canRevalidate() {
+		return nil
+	}
+
+	if etag := cached.Header.Get("ETag"); etag != "" && httpReq.Header.Get("If-None-Match") == "" {
+		httpReq.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" && httpReq.Header.Get("If-Modified-Since") == "" {
+		httpReq.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return cached
+}
+
+// notModified는 304 응답의 헤더로 저장된 응답을 갱신하고, 갱신된 응답을 반환한다.
+func (c responseCache) notModified(r *Request, cached *CachedResponse, res *http.Response) *Response {
+	res.Body.Close()
+
+	header := cached.Header.Clone()
+	for key, values := range res.Header {
+		header[key] = values
+	}
+
+	refreshed := &CachedResponse{
+		StatusCode:    cached.StatusCode,
+		Header:        header,
+		Body:          cached.Body,
+		StoredAt:      time.Now(),
+		RequestHeader: cached.RequestHeader,
+	}
+	if key, ok := c.key(r); ok {
+		c.cache.Set(key, refreshed)
+	}
+
+	return refreshed.response()
+}
+
+// store는 캐시할 수 있는 응답의 body를 모두 읽어 저장하고, 저장한 body를 다시 읽을 수 있는 reader를 반환한다.
+func (c responseCache) store(r *Request, res *http.Response, rc io.ReadCloser) (io.ReadCloser, error) {
+	if c.cache == nil || res == nil || res.StatusCode != http.StatusOK {
+		return rc, nil
+	}
+
+	key, ok := c.key(r)
+	if !ok {
+		return rc, nil
+	}
+
+	directives := parseCacheControl(res.Header.Get("Cache-Control"))
+	if _, noStore := directives["no-store"]; noStore {
+		return rc, nil
+	}
+	if _, private := directives["private"]; private {
+		return rc, nil
+	}
+
+	vary, ok := varyHeader(r, res.Header)
+	if !ok {
+		return rc, nil
+	}
+
+	// 호출자가 반환된 응답의 헤더를 수정하더라도 저장된 응답은 변경되지 않도록 복사한다.
+	cached := &CachedResponse{StatusCode: res.StatusCode, Header: res.Header.Clone(), StoredAt: time.Now(), RequestHeader: vary}
+	if !cached.canRevalidate() && !cached.isFresh(cached.StoredAt) {
+		return rc, nil
+	}
+
+	defer rc.Close()
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	cached.Body = body
+	c.cache.Set(key, cached)
+
+	return cached.reader(), nil
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(directive, "=")
+		directives[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
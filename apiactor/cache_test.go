@@ -0,0 +1,208 @@
+package apiactor_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheHandler는 경로에 따라 캐시 관련 헤더를 설정하여 응답하며, notModified가 nil이 아니면 304 응답의 수를 센다.
+func cacheHandler(notModified *atomic.Int32) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				if notModified != nil {
+					notModified.Add(1)
+				}
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/last-modified":
+			lastModified := "Mon, 02 Jan 2006 15:04:05 GMT"
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				if notModified != nil {
+					notModified.Add(1)
+				}
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", lastModified)
+		case "/max-age":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/no-store":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Cache-Control", "no-store")
+		case "/private":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/user":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write([]byte("user=" + r.Header.Get("Authorization")))
+			return
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+			w.Write([]byte("lang=" + r.Header.Get("Accept-Language")))
+			return
+		}
+		w.Write([]byte("listing"))
+	}
+}
+
+func readAll(t *testing.T, rc io.ReadCloser) string {
+	defer rc.Close()
+	body, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCache(t *testing.T) {
+	callTwice := func(t *testing.T, apiActor *apiactor.ApiActor, url string) {
+		for i := 0; i < 2; i++ {
+			rc, err := apiActor.Call(apiactor.NewRequest("GET", url))
+			require.NoError(t, err)
+			require.Equal(t, "listing", readAll(t, rc))
+		}
+	}
+
+	t.Run("ETag가 있는 응답은 If-None-Match로 재검증하고, 304 응답을 받으면 저장된 응답을 반환한다.", func(t *testing.T) {
+		notModified := &atomic.Int32{}
+		server, called := newTestServer(t, cacheHandler(notModified))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		callTwice(t, apiActor, server.URL+"/etag")
+		require.Equal(t, int32(2), called.Load())
+		require.Equal(t, int32(1), notModified.Load())
+	})
+
+	t.Run("Last-Modified가 있는 응답은 If-Modified-Since로 재검증한다.", func(t *testing.T) {
+		notModified := &atomic.Int32{}
+		server, called := newTestServer(t, cacheHandler(notModified))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		callTwice(t, apiActor, server.URL+"/last-modified")
+		require.Equal(t, int32(2), called.Load())
+		require.Equal(t, int32(1), notModified.Load())
+	})
+
+	t.Run("max-age가 지나지 않은 응답은 요청을 보내지 않고, minDelay를 기다리지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, cacheHandler(nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 1000, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		start := time.Now()
+		callTwice(t, apiActor, server.URL+"/max-age")
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+		require.Equal(t, int32(1), called.Load())
+	})
+
+	t.Run("no-store 응답은 저장하지 않는다.", func(t *testing.T) {
+		notModified := &atomic.Int32{}
+		server, called := newTestServer(t, cacheHandler(notModified))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		callTwice(t, apiActor, server.URL+"/no-store")
+		require.Equal(t, int32(2), called.Load())
+		require.Equal(t, int32(0), notModified.Load())
+	})
+
+	t.Run("private 응답은 저장하지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, cacheHandler(nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		callTwice(t, apiActor, server.URL+"/private")
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	callWithHeader := func(t *testing.T, apiActor *apiactor.ApiActor, url, key, value string) string {
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", url).SetHeader(key, value))
+		require.NoError(t, err)
+		return readAll(t, rc)
+	}
+
+	t.Run("Authorization이 다른 요청에는 저장된 응답을 사용하지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, cacheHandler(nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		require.Equal(t, "user=alice", callWithHeader(t, apiActor, server.URL+"/user", "Authorization", "alice"))
+		require.Equal(t, "user=bob", callWithHeader(t, apiActor, server.URL+"/user", "Authorization", "bob"))
+		require.Equal(t, "user=alice", callWithHeader(t, apiActor, server.URL+"/user", "Authorization", "alice"))
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("Cache를 공유하더라도 다른 Session의 요청에는 저장된 응답을 사용하지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, cacheHandler(nil))
+		cache := apiactor.NewLRUCache(10)
+		newActor := func(token string) *apiactor.ApiActor {
+			session := apiactor.NewSession().SetAuthenticator(apiactor.NewBearerAuthenticator(func(ctx context.Context) (string, error) {
+				return token, nil
+			}))
+			return apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(cache), apiactor.WithSession(session))
+		}
+		alice, bob := newActor("alice"), newActor("bob")
+
+		callUser := func(apiActor *apiactor.ApiActor) string {
+			rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/user"))
+			require.NoError(t, err)
+			return readAll(t, rc)
+		}
+
+		require.Equal(t, "user=Bearer alice", callUser(alice))
+		require.Equal(t, "user=Bearer bob", callUser(bob))
+		require.Equal(t, "user=Bearer alice", callUser(alice))
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("Vary에 지정된 요청 헤더가 다른 요청에는 저장된 응답을 사용하지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, cacheHandler(nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		require.Equal(t, "lang=ko", callWithHeader(t, apiActor, server.URL+"/vary", "Accept-Language", "ko"))
+		require.Equal(t, "lang=ko", callWithHeader(t, apiActor, server.URL+"/vary", "Accept-Language", "ko"))
+		require.Equal(t, "lang=en", callWithHeader(t, apiActor, server.URL+"/vary", "Accept-Language", "en"))
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("반환된 응답의 헤더를 수정해도 저장된 응답은 변경되지 않는다.", func(t *testing.T) {
+		server, _ := newTestServer(t, cacheHandler(nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCache(apiactor.NewLRUCache(10)))
+
+		for i := 0; i < 2; i++ {
+			res, err := apiActor.CallResponse(context.Background(), apiactor.NewRequest("GET", server.URL+"/max-age"))
+			require.NoError(t, err)
+			require.Equal(t, "max-age=60", res.Header.Get("Cache-Control"))
+			res.Header.Set("Cache-Control", "modified")
+			res.Body.Close()
+		}
+	})
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := apiactor.NewLRUCache(2)
+
+	cache.Set("a", &apiactor.CachedResponse{Body: []byte("a")})
+	cache.Set("b", &apiactor.CachedResponse{Body: []byte("b")})
+	_, ok := cache.Get("a") // a를 사용했으므로 가장 오랫동안 사용되지 않은 응답은 b이다.
+	require.True(t, ok)
+
+	cache.Set("c", &apiactor.CachedResponse{Body: []byte("c")})
+	require.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get("b")
+	require.False(t, ok)
+	_, ok = cache.Get("a")
+	require.True(t, ok)
+	_, ok = cache.Get("c")
+	require.True(t, ok)
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, cache.Len())
+}
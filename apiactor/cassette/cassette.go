@@ -0,0 +1,277 @@
+// Package cassette는 http 요청과 응답을 파일에 기록하고, 기록된 응답을 다시 반환하는 http.RoundTripper를 제공한다.
+// apiactor.WithTransport로 지정하여 실제 서버 없이 ApiActor를 사용하는 코드를 테스트할 수 있다.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+type Mode int
+
+const (
+	// ModeReplay는 기록된 응답만 반환하며, 일치하는 기록이 없으면 ErrInteractionNotFound를 반환한다.
+	ModeReplay Mode = iota
+	// ModeRecord는 실제로 요청을 보내고, Save가 호출되면 요청과 응답을 파일에 기록한다.
+	ModeRecord
+)
+
+// ErrInteractionNotFound는 ModeReplay에서 요청과 일치하는 기록이 없을 때 반환되는 에러와 errors.Is로 비교할 수 있다.
+var ErrInteractionNotFound = errors.New("cassette: interaction not found")
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"` // Recorder.MatchHeaders에 지정된 header만 기록된다.
+	Body   Body        `json:"body"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body는 UTF-8 문자열이면 그대로, 그렇지 않으면 base64로 encode하여 기록된다.
+type Body []byte
+
+type encodedBody struct {
+	Text   string `json:"text,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(encodedBody{Text: string(b)})
+	}
+	return json.Marshal(encodedBody{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var encoded encodedBody
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	if encoded.Base64 == "" {
+		*b = Body(encoded.Text)
+		return nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+type cassetteFile struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder는 method, URL, MatchHeaders에 지정된 header, body가 모두 같은 요청을 일치하는 요청으로 판단한다.
+// 일치하는 기록이 여러개라면 기록된 순서대로 한번씩만 반환하므로, 재시도와 같이 같은 요청이 반복되어도 결과가 결정적이다.
+type Recorder struct {
+	// Transport는 ModeRecord에서 실제로 요청을 보낼 때 사용된다. nil이면 http.DefaultTransport를 사용한다.
+	Transport http.RoundTripper
+	// MatchHeaders는 요청을 비교할 때 사용할 header들이다. 인증 정보 등이 기록되지 않도록 이 header들만 파일에 기록된다.
+	MatchHeaders []string
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// New는 ModeReplay인 경우 path의 파일을 읽으며, 파일이 없다면 에러를 반환한다.
+func New(path string, mode Mode, matchHeaders ...string) (*Recorder, error) {
+	r := &Recorder{
+		MatchHeaders: matchHeaders,
+		path:         path,
+		mode:         mode,
+	}
+
+	if mode == ModeReplay {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("cassette: %s: %w", r.path, err)
+	}
+
+	r.interactions = file.Interactions
+	r.replayed = make([]bool, len(file.Interactions))
+	return nil
+}
+
+// Save는 ModeRecord에서 기록된 요청과 응답들을 파일에 쓴다. ModeReplay에서는 아무것도 하지 않는다.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Interactions는 기록되었거나 파일에서 읽은 요청과 응답들을 반환한다.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.interactions)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := r.recordRequest(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req.Clone(req.Context()), recorded)
+}
+
+func (r *Recorder) recordRequest(req *http.Request, body []byte) RecordedRequest {
+	recorded := RecordedRequest{Method: req.Method, Url: req.URL.String(), Body: body}
+	for _, name := range r.MatchHeaders {
+		if values := req.Header.Values(name); len(values) > 0 {
+			if recorded.Header == nil {
+				recorded.Header = make(http.Header)
+			}
+			recorded.Header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+		}
+	}
+	return recorded
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !r.matches(interaction.Request, recorded) {
+			continue
+		}
+		r.replayed[i] = true
+		return newResponse(req, interaction.Response), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, recorded.Method, recorded.Url)
+}
+
+func (r *Recorder) matches(recorded, req RecordedRequest) bool {
+	if recorded.Method != req.Method || recorded.Url != req.Url || !bytes.Equal(recorded.Body, req.Body) {
+		return false
+	}
+
+	for _, name := range r.MatchHeaders {
+		if !slices.Equal(recorded.Header.Values(name), req.Header.Values(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if recorded.Body != nil { // 이미 읽은 body를 다시 보낸다.
+		req.Body = io.NopCloser(bytes.NewReader(recorded.Body))
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil { // 응답을 받지 못한 요청은 기록하지 않는다.
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{StatusCode: res.StatusCode, Header: res.Header.Clone(), Body: body},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return newResponse(req, interaction.Response), nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+func newResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
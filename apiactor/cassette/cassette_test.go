@@ -0,0 +1,111 @@
+package cassette_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/jae2274/goutils/apiactor/cassette"
+	"github.com/stretchr/testify/require"
+)
+
+func call(t *testing.T, apiActor *apiactor.ApiActor, r *apiactor.Request) (string, error) {
+	rc, err := apiActor.Call(r)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(body), nil
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "listing.json")
+	called := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := called.Add(1)
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("X-Count", strconv.Itoa(int(count)))
+		w.Write([]byte(r.Method + " " + r.Header.Get("Accept-Language") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	requests := func() []*apiactor.Request {
+		return []*apiactor.Request{
+			apiactor.NewRequest("GET", server.URL+"/list").SetHeader("Accept-Language", "ko"),
+			apiactor.NewRequest("GET", server.URL+"/list").SetHeader("Accept-Language", "en"),
+			apiactor.NewRequest("POST", server.URL+"/search").SetBody([]byte("keyword=golang")),
+			apiactor.NewRequest("GET", server.URL+"/list").SetHeader("Accept-Language", "ko"),
+		}
+	}
+
+	recorder, err := cassette.New(path, cassette.ModeRecord, "Accept-Language")
+	require.NoError(t, err)
+	apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithTransport(recorder))
+
+	var recorded []string
+	for _, r := range requests() {
+		body, err := call(t, apiActor, r)
+		require.NoError(t, err)
+		recorded = append(recorded, body)
+	}
+	require.Equal(t, []string{"GET ko ", "GET en ", "POST  keyword=golang", "GET ko "}, recorded)
+	require.NoError(t, recorder.Save())
+	require.Equal(t, int32(4), called.Load())
+
+	t.Run("기록된 응답을 요청한 순서대로 반환하며, 실제로 요청을 보내지 않는다.", func(t *testing.T) {
+		replayer, err := cassette.New(path, cassette.ModeReplay, "Accept-Language")
+		require.NoError(t, err)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithTransport(replayer))
+
+		for i, r := range requests() {
+			body, err := call(t, apiActor, r)
+			require.NoError(t, err)
+			require.Equal(t, recorded[i], body)
+		}
+		require.Equal(t, int32(4), called.Load())
+
+		interactions := replayer.Interactions()
+		require.Equal(t, "1", interactions[0].Response.Header.Get("X-Count"))
+		require.Equal(t, "4", interactions[3].Response.Header.Get("X-Count"))
+	})
+
+	t.Run("일치하는 기록이 없거나 이미 반환한 기록이면 ErrInteractionNotFound를 반환한다.", func(t *testing.T) {
+		replayer, err := cassette.New(path, cassette.ModeReplay, "Accept-Language")
+		require.NoError(t, err)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithTransport(replayer))
+
+		_, err = call(t, apiActor, apiactor.NewRequest("POST", server.URL+"/search").SetBody([]byte("keyword=java")))
+		require.ErrorIs(t, err, cassette.ErrInteractionNotFound)
+
+		_, err = call(t, apiActor, apiactor.NewRequest("GET", server.URL+"/list").SetHeader("Accept-Language", "en"))
+		require.NoError(t, err)
+		_, err = call(t, apiActor, apiactor.NewRequest("GET", server.URL+"/list").SetHeader("Accept-Language", "en"))
+		require.ErrorIs(t, err, cassette.ErrInteractionNotFound)
+	})
+
+	t.Run("ModeReplay에서 파일이 없으면 에러를 반환한다.", func(t *testing.T) {
+		_, err := cassette.New(filepath.Join(t.TempDir(), "none.json"), cassette.ModeReplay)
+		require.Error(t, err)
+	})
+}
+
+func TestBody(t *testing.T) {
+	for _, body := range []cassette.Body{cassette.Body("채용공고"), cassette.Body{0xff, 0xfe, 0x00}} {
+		data, err := body.MarshalJSON()
+		require.NoError(t, err)
+
+		var decoded cassette.Body
+		require.NoError(t, decoded.UnmarshalJSON(data))
+		require.Equal(t, body, decoded)
+	}
+}
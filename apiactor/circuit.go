@@ -0,0 +1,161 @@
+package apiactor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// ErrCircuitOpen은 host의 circuit이 열려있어 요청을 보내지 않았을 때 반환되는 에러와 errors.Is로 비교할 수 있다.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time // 이 시각 이후에 half-open 상태가 되어 요청을 다시 보낼 수 있다.
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s until %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreaker는 host마다 연속으로 FailureThreshold번 실패하면 circuit을 열어 CoolDown동안 요청을 바로 실패시킨다.
+// CoolDown이 지나면 half-open 상태가 되어 하나의 요청만 보내보고, 성공하면 circuit을 닫고 실패하면 다시 연다.
+type CircuitBreaker struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+	// IsFailure가 true를 반환하는 에러만 실패로 간주한다. nil이면 네트워크 에러와 5xx 응답을 실패로 간주한다.
+	IsFailure func(err error) bool
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, coolDown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		CoolDown:         coolDown,
+	}
+}
+
+func (b *CircuitBreaker) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return CircuitClosed
+	}
+	return c.state
+}
+
+func (b *CircuitBreaker) circuit(host string) *circuit {
+	if b.circuits == nil {
+		b.circuits = make(map[string]*circuit)
+	}
+
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+// allow는 host로 요청을 보낼 수 있는지 확인한다. half-open 상태에서 요청을 허용하면 probe는 true이며,
+// 요청을 보내지 못했다면 cancelProbe를, 보냈다면 record를 호출해야 한다.
+func (b *CircuitBreaker) allow(host string) (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	switch c.state {
+	case CircuitOpen:
+		retryAt := c.openedAt.Add(b.CoolDown)
+		if time.Now().Before(retryAt) {
+			return false, &CircuitOpenError{Host: host, RetryAt: retryAt}
+		}
+		c.state = CircuitHalfOpen
+		return true, nil
+	case CircuitHalfOpen: // 이미 probe 요청이 실행중이다.
+		return false, &CircuitOpenError{Host: host, RetryAt: time.Now().Add(b.CoolDown)}
+	default:
+		return false, nil
+	}
+}
+
+func (b *CircuitBreaker) cancelProbe(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c := b.circuit(host); c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+	}
+}
+
+func (b *CircuitBreaker) record(host string, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	if !b.isFailure(err) {
+		c.state = CircuitClosed
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= b.FailureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+func (b *CircuitBreaker) isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if b.IsFailure != nil {
+		return b.IsFailure(err)
+	}
+	return !IsHttpError(err) || IsServerError(err)
+}
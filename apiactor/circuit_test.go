@@ -0,0 +1,99 @@
+package apiactor_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+func hostOf(t *testing.T, rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	require.NoError(t, err)
+	return u.Host
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("연속으로 실패하면 circuit이 열리고, 열린 동안에는 요청을 보내지 않고 바로 실패한다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(math.MaxInt32, http.StatusInternalServerError, nil))
+		breaker := apiactor.NewCircuitBreaker(2, time.Minute)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCircuitBreaker(breaker))
+
+		for i := 0; i < 2; i++ {
+			_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+			require.True(t, apiactor.IsServerError(err))
+		}
+		require.Equal(t, apiactor.CircuitOpen, breaker.State(hostOf(t, server.URL)))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.ErrorIs(t, err, apiactor.ErrCircuitOpen)
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("CoolDown이 지나면 하나의 요청으로 확인하고, 성공하면 circuit을 닫는다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(1, http.StatusInternalServerError, nil))
+		breaker := apiactor.NewCircuitBreaker(1, 100*time.Millisecond)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCircuitBreaker(breaker))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsServerError(err))
+		require.Equal(t, apiactor.CircuitOpen, breaker.State(hostOf(t, server.URL)))
+
+		time.Sleep(150 * time.Millisecond)
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, apiactor.CircuitClosed, breaker.State(hostOf(t, server.URL)))
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("half-open 상태의 요청이 실패하면 circuit을 다시 연다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(math.MaxInt32, http.StatusInternalServerError, nil))
+		breaker := apiactor.NewCircuitBreaker(1, 100*time.Millisecond)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCircuitBreaker(breaker))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsServerError(err))
+
+		time.Sleep(150 * time.Millisecond)
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsServerError(err))
+		require.Equal(t, apiactor.CircuitOpen, breaker.State(hostOf(t, server.URL)))
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.ErrorIs(t, err, apiactor.ErrCircuitOpen)
+		require.Equal(t, int32(2), called.Load())
+	})
+
+	t.Run("circuit은 host마다 별도로 관리된다.", func(t *testing.T) {
+		downServer, _ := newTestServer(t, flakyHandler(math.MaxInt32, http.StatusInternalServerError, nil))
+		upServer, _ := newTestServer(t, flakyHandler(0, 0, nil))
+
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCircuitBreaker(apiactor.NewCircuitBreaker(1, time.Minute)))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", downServer.URL))
+		require.True(t, apiactor.IsServerError(err))
+		_, err = apiActor.Call(apiactor.NewRequest("GET", downServer.URL))
+		require.ErrorIs(t, err, apiactor.ErrCircuitOpen)
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", upServer.URL))
+		require.NoError(t, err)
+	})
+
+	t.Run("4xx 응답은 실패로 간주하지 않는다.", func(t *testing.T) {
+		server, _ := newTestServer(t, flakyHandler(5, http.StatusNotFound, nil))
+		breaker := apiactor.NewCircuitBreaker(1, time.Minute)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithCircuitBreaker(breaker))
+
+		for i := 0; i < 3; i++ {
+			_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+			require.True(t, apiactor.IsClientError(err))
+		}
+		require.Equal(t, apiactor.CircuitClosed, breaker.State(hostOf(t, server.URL)))
+	})
+}
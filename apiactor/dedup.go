@@ -0,0 +1,134 @@
+package apiactor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// dedup은 같은 요청들을 하나의 요청으로 합친다. nil이면 요청을 합치지 않는다.
+type dedup struct {
+	headers []string
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight는 하나로 합쳐진 요청이며, 기다리는 호출자가 모두 취소하면 요청도 취소된다.
+type flight struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+	done    chan struct{}
+
+	res  *Response // Body는 nil이며, body에 응답 body가 저장된다.
+	body []byte
+	err  error
+}
+
+func newDedup(headers []string) *dedup {
+	return &dedup{
+		headers: headers,
+		flights: make(map[string]*flight),
+	}
+}
+
+// key는 합칠 수 있는 요청이면 요청을 구분하는 key를 반환한다. body가 없는 GET, HEAD 요청만 합칠 수 있다.
+// 다른 사용자의 응답을 받지 않도록 credentialHeaders는 항상 key에 포함된다.
+func (d *dedup) key(r *Request) (string, bool) {
+	if d == nil || r.Body != nil {
+		return "", false
+	}
+
+	method := strings.ToUpper(r.Method)
+	if method != http.MethodGet && method != http.MethodHead {
+		return "", false
+	}
+
+	fullUrl, err := r.fullUrl()
+	if err != nil {
+		return "", false
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(method + " " + fullUrl + credentialKey(r.Header))
+	for _, header := range d.headers {
+		sb.WriteString("\n" + http.CanonicalHeaderKey(header) + ": " + strings.Join(r.Header.Values(header), ","))
+	}
+	return sb.String(), true
+}
+
+// do는 key에 해당하는 요청이 없으면 call로 요청하고, 있으면 그 결과를 기다린다.
+func (d *dedup) do(ctx context.Context, key string, r *Request, call func(context.Context, *Request) (*Response, error)) (*Response, error) {
+	d.mu.Lock()
+	f, ok := d.flights[key]
+	if !ok {
+		// 요청은 먼저 호출한 호출자가 취소하더라도 다른 호출자들을 위해 계속되어야 하므로, ctx의 값만 사용한다.
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{ctx: flightCtx, cancel: cancel, done: make(chan struct{})}
+		d.flights[key] = f
+		go d.run(key, f, r, call)
+	}
+	f.waiters++
+	d.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		res := *f.res
+		res.Header = f.res.Header.Clone()
+		res.Body = io.NopCloser(bytes.NewReader(f.body))
+		return &res, nil
+	case <-ctx.Done():
+		d.leave(key, f)
+		return nil, callerCancelledError(ctx)
+	}
+}
+
+func (d *dedup) run(key string, f *flight, r *Request, call func(context.Context, *Request) (*Response, error)) {
+	defer f.cancel()
+	defer close(f.done)
+
+	res, err := call(f.ctx, r)
+	if err == nil {
+		f.body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			err = &RequestError{Method: r.Method, Url: r.Url, Err: err}
+		}
+	}
+
+	d.mu.Lock()
+	if d.flights[key] == f {
+		delete(d.flights, key)
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		f.err = err
+		return
+	}
+	res.Body = nil
+	f.res = res
+}
+
+// leave는 호출자가 더 이상 결과를 기다리지 않을 때 호출되며, 기다리는 호출자가 없으면 요청을 취소한다.
+func (d *dedup) leave(key string, f *flight) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	if d.flights[key] == f {
+		delete(d.flights, key)
+	}
+	f.cancel()
+}
@@ -0,0 +1,134 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+// dedupHandler는 delay 후에 요청의 method와 Accept-Language로 응답하며, cancelled가 nil이 아니면 그 전에 취소된 요청의 수를 센다.
+func dedupHandler(delay time.Duration, cancelled *atomic.Int32) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(r.Method + " " + r.Header.Get("Accept-Language")))
+		case <-r.Context().Done():
+			if cancelled != nil {
+				cancelled.Add(1)
+			}
+		}
+	}
+}
+
+func TestDedup(t *testing.T) {
+	callAll := func(apiActor *apiactor.ApiActor, reqs ...*apiactor.Request) ([]string, []error) {
+		bodies, errs := make([]string, len(reqs)), make([]error, len(reqs))
+		wg := sync.WaitGroup{}
+		for i, req := range reqs {
+			wg.Add(1)
+			go func(i int, req *apiactor.Request) {
+				defer wg.Done()
+				rc, err := apiActor.Call(req)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				bodies[i] = readAll(t, rc)
+			}(i, req)
+		}
+		wg.Wait()
+		return bodies, errs
+	}
+
+	t.Run("같은 요청들은 하나의 요청으로 합쳐지고, 각 호출자는 별도의 body를 받는다.", func(t *testing.T) {
+		server, called := newTestServer(t, dedupHandler(100*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDedup("Accept-Language"))
+
+		bodies, errs := callAll(apiActor,
+			apiactor.NewRequest("GET", server.URL).SetHeader("Accept-Language", "ko"),
+			apiactor.NewRequest("get", server.URL).SetHeader("Accept-Language", "ko"),
+			apiactor.NewRequest("GET", server.URL).SetHeader("Accept-Language", "ko"),
+		)
+		require.Equal(t, []error{nil, nil, nil}, errs)
+		require.Equal(t, []string{"GET ko", "GET ko", "GET ko"}, bodies)
+		require.Equal(t, int32(1), called.Load())
+	})
+
+	t.Run("지정한 header가 다르거나 body가 있는 요청은 합치지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, dedupHandler(100*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDedup("Accept-Language"), apiactor.WithLimiter(apiactor.NewTokenBucketLimiter(100, 100, 0)))
+
+		_, errs := callAll(apiActor,
+			apiactor.NewRequest("GET", server.URL).SetHeader("Accept-Language", "ko"),
+			apiactor.NewRequest("GET", server.URL).SetHeader("Accept-Language", "en"),
+			apiactor.NewRequest("POST", server.URL).SetBody([]byte("a")),
+			apiactor.NewRequest("POST", server.URL).SetBody([]byte("a")),
+		)
+		require.Equal(t, []error{nil, nil, nil, nil}, errs)
+		require.Equal(t, int32(4), called.Load())
+	})
+
+	t.Run("지정하지 않아도 Authorization, Cookie가 다른 요청은 합치지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, dedupHandler(100*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDedup(), apiactor.WithLimiter(apiactor.NewTokenBucketLimiter(100, 100, 0)))
+
+		_, errs := callAll(apiActor,
+			apiactor.NewRequest("GET", server.URL).SetHeader("Authorization", "alice"),
+			apiactor.NewRequest("GET", server.URL).SetHeader("Authorization", "bob"),
+			apiactor.NewRequest("GET", server.URL).SetHeader("Cookie", "session=alice"),
+			apiactor.NewRequest("GET", server.URL).SetHeader("Cookie", "session=bob"),
+		)
+		require.Equal(t, []error{nil, nil, nil, nil}, errs)
+		require.Equal(t, int32(4), called.Load())
+	})
+
+	t.Run("먼저 호출한 호출자가 취소하더라도, 기다리는 다른 호출자는 응답을 받는다.", func(t *testing.T) {
+		cancelled := &atomic.Int32{}
+		server, called := newTestServer(t, dedupHandler(200*time.Millisecond, cancelled))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDedup())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", server.URL))
+			errChan <- err
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, "GET ", readAll(t, rc))
+		require.ErrorIs(t, <-errChan, apiactor.ErrCallerCancelled)
+		require.Equal(t, int32(1), called.Load())
+		require.Equal(t, int32(0), cancelled.Load())
+	})
+
+	t.Run("기다리는 호출자가 모두 취소하면 요청도 취소된다.", func(t *testing.T) {
+		cancelled := &atomic.Int32{}
+		server, called := newTestServer(t, dedupHandler(time.Second, cancelled))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithDedup())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		wg := sync.WaitGroup{}
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", server.URL))
+				require.ErrorIs(t, err, apiactor.ErrCallerCancelled)
+			}()
+		}
+		wg.Wait()
+
+		require.Eventually(t, func() bool { return cancelled.Load() == 1 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, int32(1), called.Load())
+	})
+}
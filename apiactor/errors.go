@@ -0,0 +1,56 @@
+package apiactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrActorStopped는 ApiActor가 종료되어 요청을 실행할 수 없을 때 반환된다.
+	ErrActorStopped = errors.New("api actor stopped")
+	// ErrCallerCancelled는 요청이 실행되기 전 혹은 실행 도중 호출자의 context가 종료되었을 때 반환된다.
+	ErrCallerCancelled = errors.New("caller cancelled")
+)
+
+func callerCancelledError(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrCallerCancelled, ctx.Err())
+}
+
+// RequestError는 요청을 실행하였으나 실패했을 때 반환된다. Err은 *HttpError 혹은 네트워크 에러 등이다.
+type RequestError struct {
+	Method string
+	Url    string
+	Err    error
+	// Idempotent는 요청의 method가 멱등이거나 Request.Idempotent가 true인지 여부로, RetryPolicy가 재시도 여부를 판단할 때 사용한다.
+	Idempotent bool
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Url, e.Err.Error())
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+func IsRequestError(err error) bool {
+	var reqErr *RequestError
+	return errors.As(err, &reqErr)
+}
+
+// DecodeError는 응답 body를 decode하지 못했을 때 반환되며, Snippet은 응답 body의 앞부분이다.
+type DecodeError struct {
+	Method  string
+	Url     string
+	Snippet []byte
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode %s %s: %s (body: %q)", e.Method, e.Url, e.Err.Error(), e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
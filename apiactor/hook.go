@@ -0,0 +1,98 @@
+package apiactor
+
+import "time"
+
+// CallInfo는 Hook에 전달되는 요청의 정보이다. 재시도하는 요청은 시도마다 OnEnqueue, OnStart, OnFinish가 호출되며 Attempt가 1씩 증가한다.
+type CallInfo struct {
+	Method   string
+	Url      string
+	Host     string
+	Priority int
+	Attempt  int // 1부터 시작한다.
+
+	EnqueuedAt time.Time
+	StartedAt  time.Time // 요청을 보내지 않고 대기열에서 제거된 경우 zero value이다.
+	FinishedAt time.Time
+
+	StatusCode int // 응답을 받지 못한 경우 0이다.
+	Err        error
+}
+
+// WaitTime은 대기열에 들어온 후 요청을 보내기까지 혹은 대기열에서 제거되기까지의 시간이다.
+func (i CallInfo) WaitTime() time.Duration {
+	if i.StartedAt.IsZero() {
+		return i.FinishedAt.Sub(i.EnqueuedAt)
+	}
+	return i.StartedAt.Sub(i.EnqueuedAt)
+}
+
+// Latency는 요청을 보낸 후 응답을 받기까지의 시간이며, 요청을 보내지 않은 경우 0이다.
+func (i CallInfo) Latency() time.Duration {
+	if i.StartedAt.IsZero() || i.FinishedAt.IsZero() {
+		return 0
+	}
+	return i.FinishedAt.Sub(i.StartedAt)
+}
+
+// Hook은 ApiActor의 요청 처리 과정을 관찰한다. 모든 OnEnqueue 이후에는 OnStart와 OnFinish가 차례로 호출되거나,
+// 요청을 보내지 않고 대기열에서 제거된 경우 StartedAt이 zero value인 OnFinish만 호출된다.
+// Hook은 ApiActor의 여러 goroutine에서 동시에 호출되며, 호출되는 동안 요청의 처리가 지연되므로 빠르게 반환해야 한다.
+type Hook interface {
+	OnEnqueue(info CallInfo)
+	OnStart(info CallInfo)
+	OnFinish(info CallInfo)
+}
+
+type hooks []Hook
+
+func (h hooks) enqueue(rj *requestJob) {
+	if len(h) == 0 {
+		return
+	}
+
+	rj.queuedAt = time.Now()
+	info := rj.info()
+	for _, hook := range h {
+		hook.OnEnqueue(info)
+	}
+}
+
+func (h hooks) start(rj *requestJob) {
+	if len(h) == 0 {
+		return
+	}
+
+	rj.startedAt = time.Now()
+	info := rj.info()
+	for _, hook := range h {
+		hook.OnStart(info)
+	}
+}
+
+// finish는 요청을 보내지 않고 대기열에서 제거된 경우에도 호출되어야 한다.
+func (h hooks) finish(rj *requestJob, statusCode int, err error) {
+	if len(h) == 0 {
+		return
+	}
+
+	info := rj.info()
+	info.FinishedAt = time.Now()
+	info.StatusCode = statusCode
+	info.Err = err
+	for _, hook := range h {
+		hook.OnFinish(info)
+	}
+	rj.startedAt = time.Time{}
+}
+
+func (rj *requestJob) info() CallInfo {
+	return CallInfo{
+		Method:     rj.request.Method,
+		Url:        rj.request.Url,
+		Host:       hostOf(rj.request),
+		Priority:   rj.request.Priority,
+		Attempt:    rj.attempt + 1,
+		EnqueuedAt: rj.queuedAt,
+		StartedAt:  rj.startedAt,
+	}
+}
@@ -0,0 +1,56 @@
+package apiactor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/jae2274/goutils/jjson"
+)
+
+const decodeSnippetSize = 1024
+
+// CallJSON은 응답 body를 T로 decode하고 body를 닫는다. decode에 실패하면 *DecodeError를 반환한다.
+func CallJSON[T any](ctx context.Context, a *ApiActor, r *Request) (*T, error) {
+	rc, err := a.CallContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSON[T](r.Method, r.Url, rc)
+}
+
+func CallApiJSON[T any](httpReq *http.Request, opts ...Option) (*T, error) {
+	rc, err := CallApi(httpReq, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSON[T](httpReq.Method, httpReq.URL.String(), rc)
+}
+
+func decodeJSON[T any](method, url string, rc io.ReadCloser) (*T, error) {
+	defer rc.Close()
+
+	snippet := &snippetWriter{limit: decodeSnippetSize}
+	value, err := jjson.UnmarshalReader[T](io.TeeReader(rc, snippet))
+	if err != nil {
+		return nil, &DecodeError{Method: method, Url: url, Snippet: snippet.Bytes(), Err: err}
+	}
+
+	return value, nil
+}
+
+// snippetWriter는 처음 limit 바이트까지만 저장하고 나머지는 버린다.
+type snippetWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+func (w *snippetWriter) Write(p []byte) (int, error) {
+	if remained := w.limit - w.Len(); remained > 0 {
+		w.Buffer.Write(p[:min(len(p), remained)])
+	}
+	return len(p), nil
+}
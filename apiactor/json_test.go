@@ -0,0 +1,77 @@
+package apiactor_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type Posting struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCallJSON(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		switch r.URL.Path {
+		case "/posting":
+			w.Write([]byte(`{"id":1,"title":"Go Developer"}`))
+		case "/html":
+			w.Write([]byte(`<html><body>maintenance</body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+	t.Run("응답 body를 T로 decode한다.", func(t *testing.T) {
+		posting, err := apiactor.CallJSON[Posting](context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/posting"))
+		require.NoError(t, err)
+		require.Equal(t, &Posting{Id: 1, Title: "Go Developer"}, posting)
+	})
+
+	t.Run("decode에 실패하면 요청 URL과 응답 body의 일부를 포함한 DecodeError를 반환한다.", func(t *testing.T) {
+		_, err := apiactor.CallJSON[Posting](context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/html"))
+
+		var decodeErr *apiactor.DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, server.URL+"/html", decodeErr.Url)
+		require.Contains(t, string(decodeErr.Snippet), "<html>")
+	})
+
+	t.Run("요청이 실패하면 RequestError를 그대로 반환한다.", func(t *testing.T) {
+		_, err := apiactor.CallJSON[Posting](context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/not-found"))
+		require.True(t, apiactor.IsRequestError(err))
+	})
+
+	t.Run("CallApiJSON은 decode 후 body를 닫는다.", func(t *testing.T) {
+		body := &closeTracker{Reader: strings.NewReader(`{"id":2,"title":"Backend"}`)}
+		transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: body, Request: r}, nil
+		})
+
+		httpReq, err := http.NewRequest("GET", "http://stub.invalid", nil)
+		require.NoError(t, err)
+
+		posting, err := apiactor.CallApiJSON[Posting](httpReq, apiactor.WithTransport(transport))
+		require.NoError(t, err)
+		require.Equal(t, &Posting{Id: 2, Title: "Backend"}, posting)
+		require.True(t, body.closed)
+	})
+}
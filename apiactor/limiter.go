@@ -0,0 +1,147 @@
+package apiactor
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter는 ApiActor가 대기중인 요청을 언제 실행할지 결정한다.
+// Reserve는 ApiActor의 goroutine에서만 호출되지만, release 함수는 요청을 실행한 goroutine에서 호출된다.
+type Limiter interface {
+	// Reserve는 r을 바로 실행할 수 있으면 실행이 끝난 후 호출해야 하는 release 함수와 true를 반환한다.
+	// 바로 실행할 수 없으면 다시 시도할 때까지의 대기시간과 false를 반환하며, 대기시간이 0이면 실행중인 요청이 release될 때까지 기다린다.
+	Reserve(r *Request) (release func(), wait time.Duration, ok bool)
+}
+
+// DelayLimiter는 한번에 하나의 요청만 실행하며, 이전 요청이 끝난 후 최소 minDelay 이후에 다음 요청을 실행한다.
+type DelayLimiter struct {
+	mu        sync.Mutex
+	minDelay  time.Duration
+	running   bool
+	lastEnded time.Time
+}
+
+func NewDelayLimiter(minDelay time.Duration) *DelayLimiter {
+	return &DelayLimiter{minDelay: minDelay}
+}
+
+func (l *DelayLimiter) Reserve(r *Request) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running {
+		return nil, 0, false
+	}
+
+	if wait := time.Until(l.lastEnded.Add(l.minDelay)); wait > 0 {
+		return nil, wait, false
+	}
+
+	l.running = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.running = false
+		l.lastEnded = time.Now()
+	}, 0, true
+}
+
+// TokenBucketLimiter는 초당 rate개의 토큰을 최대 burst개까지 채우며, 요청마다 토큰을 하나씩 소모한다.
+// maxConcurrent가 0보다 크면 동시에 실행중인 요청의 수를 maxConcurrent개로 제한한다.
+type TokenBucketLimiter struct {
+	mu            sync.Mutex
+	rate          float64
+	burst         float64
+	maxConcurrent int
+
+	tokens     float64
+	inFlight   int
+	lastRefill time.Time
+}
+
+func NewTokenBucketLimiter(rate float64, burst int, maxConcurrent int) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		rate:          rate,
+		burst:         float64(burst),
+		maxConcurrent: maxConcurrent,
+		tokens:        float64(burst),
+		lastRefill:    time.Now(),
+	}
+}
+
+func (l *TokenBucketLimiter) Reserve(r *Request) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxConcurrent > 0 && l.inFlight >= l.maxConcurrent {
+		return nil, 0, false
+	}
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.lastRefill).Seconds()*l.rate)
+	l.lastRefill = now
+
+	if l.tokens < 1 {
+		return nil, time.Duration((1 - l.tokens) / l.rate * float64(time.Second)), false
+	}
+
+	l.tokens--
+	l.inFlight++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.inFlight--
+	}, 0, true
+}
+
+// HostLimiter는 요청 URL의 host마다 별도의 Limiter를 생성하여 적용한다.
+type HostLimiter struct {
+	mu         sync.Mutex
+	newLimiter func(host string) Limiter
+	limiters   map[string]Limiter
+}
+
+func NewHostLimiter(newLimiter func(host string) Limiter) *HostLimiter {
+	return &HostLimiter{
+		newLimiter: newLimiter,
+		limiters:   make(map[string]Limiter),
+	}
+}
+
+func (l *HostLimiter) Reserve(r *Request) (func(), time.Duration, bool) {
+	return l.limiter(hostOf(r)).Reserve(r)
+}
+
+// Observe는 host의 Limiter가 ResponseObserver를 구현하면 응답을 전달한다.
+func (l *HostLimiter) Observe(r *Request, res *http.Response) {
+	if observer, ok := l.limiter(hostOf(r)).(ResponseObserver); ok {
+		observer.Observe(r, res)
+	}
+}
+
+func (l *HostLimiter) limiter(host string) Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[host]
+	if !ok {
+		limiter = l.newLimiter(host)
+		l.limiters[host] = limiter
+	}
+
+	return limiter
+}
+
+func hostOf(r *Request) string {
+	u, err := url.Parse(r.Url)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,142 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type concurrencyCounter struct {
+	current atomic.Int32
+	max     atomic.Int32
+}
+
+// enter는 요청의 처리를 시작할 때 호출되어 최대 동시 처리 수를 갱신하며, 처리가 끝나면 반환된 함수를 호출해야 한다.
+func (c *concurrencyCounter) enter() func() {
+	current := c.current.Add(1)
+	for {
+		max := c.max.Load()
+		if current <= max || c.max.CompareAndSwap(max, current) {
+			break
+		}
+	}
+	return func() { c.current.Add(-1) }
+}
+
+// slowHandler는 delay 후에 응답하며, counter가 nil이 아니면 동시에 처리한 요청의 수를 센다.
+func slowHandler(delay time.Duration, counter *concurrencyCounter) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		if counter != nil {
+			defer counter.enter()()
+		}
+
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	}
+}
+
+func callConcurrently(t *testing.T, apiActor *apiactor.ApiActor, urls ...string) {
+	wg := sync.WaitGroup{}
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			_, err := apiActor.Call(apiactor.NewRequest("GET", url))
+			require.NoError(t, err)
+		}(url)
+	}
+	wg.Wait()
+}
+
+func repeat(value string, count int) []string {
+	values := make([]string, count)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
+
+func TestDelayLimiter(t *testing.T) {
+	limiter := apiactor.NewDelayLimiter(100 * time.Millisecond)
+	req := apiactor.NewRequest("GET", "http://localhost")
+
+	release, _, ok := limiter.Reserve(req)
+	require.True(t, ok)
+
+	_, wait, ok := limiter.Reserve(req) // 실행중인 요청이 있으면 release될 때까지 기다린다.
+	require.False(t, ok)
+	require.Zero(t, wait)
+
+	release()
+	_, wait, ok = limiter.Reserve(req) // release 이후에는 minDelay만큼 기다린다.
+	require.False(t, ok)
+	require.Greater(t, wait, 50*time.Millisecond)
+
+	time.Sleep(wait)
+	_, _, ok = limiter.Reserve(req)
+	require.True(t, ok)
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	t.Run("burst만큼은 바로 실행되고, 이후로는 rate에 맞춰 실행된다.", func(t *testing.T) {
+		limiter := apiactor.NewTokenBucketLimiter(10, 2, 0)
+		req := apiactor.NewRequest("GET", "http://localhost")
+
+		for i := 0; i < 2; i++ {
+			release, _, ok := limiter.Reserve(req)
+			require.True(t, ok)
+			release()
+		}
+
+		_, wait, ok := limiter.Reserve(req) // token이 다시 채워지는 1/rate초만큼 기다린다.
+		require.False(t, ok)
+		require.Greater(t, wait, 50*time.Millisecond)
+		require.LessOrEqual(t, wait, 100*time.Millisecond)
+
+		time.Sleep(wait)
+		_, _, ok = limiter.Reserve(req)
+		require.True(t, ok)
+	})
+
+	t.Run("동시에 실행되는 요청의 수는 maxConcurrent를 넘지 않는다.", func(t *testing.T) {
+		counter := &concurrencyCounter{}
+		server, _ := newTestServer(t, slowHandler(100*time.Millisecond, counter))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithLimiter(apiactor.NewTokenBucketLimiter(1000, 1000, 2)))
+
+		callConcurrently(t, apiActor, repeat(server.URL, 6)...)
+		require.Equal(t, int32(2), counter.max.Load())
+	})
+}
+
+func TestHostLimiter(t *testing.T) {
+	total := &concurrencyCounter{}
+	newHostServer := func(counter *concurrencyCounter) *httptest.Server {
+		handler := slowHandler(200*time.Millisecond, counter)
+		server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+			defer total.enter()()
+			handler(w, r, count)
+		})
+		return server
+	}
+	counter1, counter2 := &concurrencyCounter{}, &concurrencyCounter{}
+	server1, server2 := newHostServer(counter1), newHostServer(counter2)
+
+	limiter := apiactor.NewHostLimiter(func(host string) apiactor.Limiter {
+		return apiactor.NewTokenBucketLimiter(1000, 1000, 2)
+	})
+	apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithLimiter(limiter))
+
+	callConcurrently(t, apiActor, append(repeat(server1.URL, 4), repeat(server2.URL, 4)...)...)
+
+	// host마다 별도로 동시 실행 수가 제한되므로, 두 host의 요청은 서로를 기다리지 않고 함께 실행된다.
+	require.Equal(t, int32(2), counter1.max.Load())
+	require.Equal(t, int32(2), counter2.max.Load())
+	require.Equal(t, int32(4), total.max.Load())
+}
@@ -0,0 +1,187 @@
+package apiactor
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets는 대기시간과 응답시간 histogram의 기본 bucket으로, 초 단위이다.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics는 Hook으로 수집한 대기열 크기, 대기시간, 응답시간, status code별 요청 수를 Prometheus text 형식으로 제공하는 http.Handler이다.
+type Metrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	queued   int64
+	inFlight int64
+	requests map[requestLabels]uint64
+	waitTime *histogram
+	latency  map[string]*histogram // host별 응답시간
+}
+
+type requestLabels struct {
+	host   string
+	method string
+	status string // 응답을 받지 못한 경우 "error"이다.
+}
+
+type histogram struct {
+	counts []uint64 // counts[i]는 buckets[i] 이하인 값의 수이다.
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(buckets []float64, value float64) {
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// NewMetrics는 buckets를 지정하지 않으면 DefaultBuckets를 사용한다.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &Metrics{
+		buckets:  buckets,
+		requests: make(map[requestLabels]uint64),
+		waitTime: newHistogram(buckets),
+		latency:  make(map[string]*histogram),
+	}
+}
+
+func (m *Metrics) OnEnqueue(info CallInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queued++
+}
+
+func (m *Metrics) OnStart(info CallInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queued--
+	m.inFlight++
+	m.waitTime.observe(m.buckets, info.WaitTime().Seconds())
+}
+
+func (m *Metrics) OnFinish(info CallInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if info.StartedAt.IsZero() { // 요청을 보내지 않고 대기열에서 제거되었다.
+		m.queued--
+		m.waitTime.observe(m.buckets, info.WaitTime().Seconds())
+		return
+	}
+
+	m.inFlight--
+
+	latency, ok := m.latency[info.Host]
+	if !ok {
+		latency = newHistogram(m.buckets)
+		m.latency[info.Host] = latency
+	}
+	latency.observe(m.buckets, info.Latency().Seconds())
+
+	status := "error"
+	if info.StatusCode > 0 {
+		status = strconv.Itoa(info.StatusCode)
+	}
+	m.requests[requestLabels{info.Host, strings.ToUpper(info.Method), status}]++
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo는 수집한 metric들을 Prometheus text 형식으로 w에 쓴다.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sb := &strings.Builder{}
+
+	writeHeader(sb, "apiactor_queue_depth", "gauge", "Number of requests waiting in the queue.")
+	fmt.Fprintf(sb, "apiactor_queue_depth %d\n", m.queued)
+
+	writeHeader(sb, "apiactor_in_flight", "gauge", "Number of requests being executed.")
+	fmt.Fprintf(sb, "apiactor_in_flight %d\n", m.inFlight)
+
+	writeHeader(sb, "apiactor_requests_total", "counter", "Number of executed requests by host, method and status code.")
+	labels := make([]requestLabels, 0, len(m.requests))
+	for l := range m.requests {
+		labels = append(labels, l)
+	}
+	slices.SortFunc(labels, func(l1, l2 requestLabels) int {
+		if c := cmp.Compare(l1.host, l2.host); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(l1.method, l2.method); c != 0 {
+			return c
+		}
+		return cmp.Compare(l1.status, l2.status)
+	})
+	for _, l := range labels {
+		fmt.Fprintf(sb, "apiactor_requests_total{host=%s,method=%s,status=%s} %d\n", quoteLabel(l.host), quoteLabel(l.method), quoteLabel(l.status), m.requests[l])
+	}
+
+	writeHeader(sb, "apiactor_wait_seconds", "histogram", "Time requests spent waiting in the queue.")
+	m.writeHistogram(sb, "apiactor_wait_seconds", "", m.waitTime)
+
+	writeHeader(sb, "apiactor_request_duration_seconds", "histogram", "Latency of executed requests by host.")
+	hosts := make([]string, 0, len(m.latency))
+	for host := range m.latency {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		m.writeHistogram(sb, "apiactor_request_duration_seconds", "host="+quoteLabel(host)+",", m.latency[host])
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func (m *Metrics) writeHistogram(sb *strings.Builder, name, labels string, h *histogram) {
+	for i, bound := range m.buckets {
+		fmt.Fprintf(sb, "%s_bucket{%sle=\"%s\"} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(sb, "%s_bucket{%sle=\"+Inf\"} %d\n", name, labels, h.count)
+
+	labels = strings.TrimSuffix(labels, ",")
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(sb, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(sb, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func writeHeader(sb *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
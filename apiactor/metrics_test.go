@@ -0,0 +1,126 @@
+package apiactor_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type hookEvent struct {
+	name string
+	info apiactor.CallInfo
+}
+
+type recordingHook struct {
+	mu     sync.Mutex
+	events []hookEvent
+}
+
+func (h *recordingHook) record(name string, info apiactor.CallInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, hookEvent{name, info})
+}
+
+func (h *recordingHook) OnEnqueue(info apiactor.CallInfo) { h.record("enqueue", info) }
+func (h *recordingHook) OnStart(info apiactor.CallInfo)   { h.record("start", info) }
+func (h *recordingHook) OnFinish(info apiactor.CallInfo)  { h.record("finish", info) }
+
+func TestHook(t *testing.T) {
+	t.Run("요청마다 OnEnqueue, OnStart, OnFinish가 차례로 호출된다.", func(t *testing.T) {
+		server, _ := newTestServer(t, flakyHandler(1, http.StatusServiceUnavailable, nil))
+		hook := &recordingHook{}
+		apiActor := apiactor.NewApiActor(context.Background(), 0,
+			apiactor.WithHooks(hook),
+			apiactor.WithRetryPolicy(&apiactor.BackoffRetryPolicy{MaxAttempts: 2, RetryableStatusCodes: apiactor.DefaultRetryableStatusCodes}),
+		)
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, event := range hook.events {
+			names = append(names, event.name)
+		}
+		require.Equal(t, []string{"enqueue", "start", "finish", "enqueue", "start", "finish"}, names)
+
+		firstFinish, lastFinish := hook.events[2].info, hook.events[5].info
+		require.Equal(t, 1, firstFinish.Attempt)
+		require.Equal(t, http.StatusServiceUnavailable, firstFinish.StatusCode)
+		require.Error(t, firstFinish.Err)
+		require.Equal(t, 2, lastFinish.Attempt)
+		require.Equal(t, http.StatusOK, lastFinish.StatusCode)
+		require.NoError(t, lastFinish.Err)
+		require.Greater(t, lastFinish.Latency(), time.Duration(0))
+	})
+
+	t.Run("요청을 보내지 않고 대기열에서 제거되면 OnStart 없이 OnFinish가 호출된다.", func(t *testing.T) {
+		slowServer, _ := newTestServer(t, slowHandler(200*time.Millisecond, nil))
+		hook := &recordingHook{}
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithHooks(hook))
+
+		go apiActor.Call(apiactor.NewRequest("GET", slowServer.URL))
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", slowServer.URL+"/cancelled"))
+		require.ErrorIs(t, err, apiactor.ErrCallerCancelled)
+		time.Sleep(50 * time.Millisecond)
+
+		hook.mu.Lock()
+		defer hook.mu.Unlock()
+
+		var cancelled []hookEvent
+		for _, event := range hook.events {
+			if event.info.Url == slowServer.URL+"/cancelled" {
+				cancelled = append(cancelled, event)
+			}
+		}
+		require.Len(t, cancelled, 2)
+		require.Equal(t, "enqueue", cancelled[0].name)
+		require.Equal(t, "finish", cancelled[1].name)
+		require.True(t, cancelled[1].info.StartedAt.IsZero())
+		require.ErrorIs(t, cancelled[1].info.Err, apiactor.ErrCallerCancelled)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	server, _ := newTestServer(t, flakyHandler(1, http.StatusNotFound, nil))
+	metrics := apiactor.NewMetrics(0.1, 1)
+	apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithHooks(metrics))
+
+	for i := 0; i < 3; i++ {
+		apiActor.Call(apiactor.NewRequest("GET", server.URL))
+	}
+
+	metricsServer := httptest.NewServer(metrics)
+	defer metricsServer.Close()
+
+	res, err := http.Get(metricsServer.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", res.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	host := hostOf(t, server.URL)
+	exposition := string(body)
+	require.Contains(t, exposition, "# TYPE apiactor_queue_depth gauge\napiactor_queue_depth 0\n")
+	require.Contains(t, exposition, "# TYPE apiactor_in_flight gauge\napiactor_in_flight 0\n")
+	require.Contains(t, exposition, `apiactor_requests_total{host="`+host+`",method="GET",status="200"} 2`+"\n")
+	require.Contains(t, exposition, `apiactor_requests_total{host="`+host+`",method="GET",status="404"} 1`+"\n")
+	require.Contains(t, exposition, "# TYPE apiactor_wait_seconds histogram\n")
+	require.Contains(t, exposition, "apiactor_wait_seconds_bucket{le=\"+Inf\"} 3\n")
+	require.Contains(t, exposition, "apiactor_wait_seconds_count 3\n")
+	require.Contains(t, exposition, `apiactor_request_duration_seconds_bucket{host="`+host+`",le="1"} 3`+"\n")
+	require.Contains(t, exposition, `apiactor_request_duration_seconds_count{host="`+host+`"} 3`+"\n")
+}
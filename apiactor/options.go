@@ -0,0 +1,170 @@
+package apiactor
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultPriorityAging마다 대기중인 요청의 Priority가 1씩 올라가므로, 낮은 Priority의 요청도 결국 실행된다.
+const DefaultPriorityAging = time.Second
+
+// DefaultClient는 옵션으로 http.Client를 지정하지 않았을 때 사용된다.
+var DefaultClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+type options struct {
+	client       *http.Client
+	transport    http.RoundTripper
+	retryPolicy  RetryPolicy
+	limiter      Limiter
+	aging        time.Duration
+	acceptStatus func(int) bool
+	cache        Cache
+	breaker      *CircuitBreaker
+	hooks        hooks
+	propagation  propagation
+	dedup        *dedup
+	body         bodyOptions
+	session      *Session
+}
+
+type Option func(*options)
+
+func newOptions(opts []Option) *options {
+	o := &options{aging: DefaultPriorityAging, propagation: defaultPropagation()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) httpClient() *http.Client {
+	client := o.client
+	if client == nil {
+		client = DefaultClient
+	}
+
+	if o.transport != nil {
+		withTransport := *client
+		withTransport.Transport = o.transport
+		client = &withTransport
+	}
+
+	return client
+}
+
+// WithHttpClient는 CallApi와 ApiActor가 요청을 전송할 http.Client를 지정한다.
+func WithHttpClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithTransport는 http.Client의 Transport만 교체한다. WithHttpClient와 함께 사용하면 지정한 http.Client의 Transport를 교체한다.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+func WithRetryPolicy(retryPolicy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = retryPolicy
+	}
+}
+
+func WithLimiter(limiter Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithPriorityAging은 대기중인 요청의 Priority를 aging마다 1씩 올린다. aging이 0 이하이면 Priority를 올리지 않는다.
+func WithPriorityAging(aging time.Duration) Option {
+	return func(o *options) {
+		o.aging = aging
+	}
+}
+
+// WithAcceptStatus는 성공으로 처리할 status code를 결정한다. 지정하지 않으면 IsSuccessStatus를 사용하며, Request.AcceptStatus가 우선한다.
+func WithAcceptStatus(acceptStatus func(statusCode int) bool) Option {
+	return func(o *options) {
+		o.acceptStatus = acceptStatus
+	}
+}
+
+// WithCache는 GET 요청의 응답을 cache에 저장하고, 이후의 요청에 If-None-Match, If-Modified-Since 헤더를 설정하여 재검증한다.
+// 304 응답을 받으면 저장된 응답을 반환하며, Cache-Control의 max-age가 지나지 않은 응답은 요청을 보내지 않고 바로 반환한다.
+func WithCache(cache Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithCircuitBreaker는 host마다 circuit을 관리하여, circuit이 열린 host로의 요청을 *CircuitOpenError로 바로 실패시킨다.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(o *options) {
+		o.breaker = breaker
+	}
+}
+
+// WithHooks는 요청이 대기열에 들어오고, 실행되고, 끝날 때마다 hooks를 호출한다.
+func WithHooks(hooks ...Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// WithGenerateTraceId는 context에 trace id가 없는 경우 새로 생성하여 X-Request-Id 헤더로 보낸다.
+// 지정하지 않아도 context에 mw.CtxKeyTraceID로 저장된 trace id는 X-Request-Id 헤더로 전달된다.
+func WithGenerateTraceId() Option {
+	return func(o *options) {
+		o.propagation.generateTrace = true
+	}
+}
+
+// WithCtxHeader는 context에 ctxKey로 저장된 문자열 값을 header로 전달한다. 요청에 이미 설정된 header는 덮어쓰지 않는다.
+func WithCtxHeader(ctxKey any, header string) Option {
+	return func(o *options) {
+		o.propagation.headers = append(o.propagation.headers, ctxHeader{ctxKey, header})
+	}
+}
+
+// WithDedup은 method, URL, Authorization, Cookie와 headers에 지정된 header들이 같은 GET, HEAD 요청이 이미 대기중이거나 실행중이면, 새로 요청하지 않고 그 응답을 함께 받는다.
+// 응답 body는 모두 읽은 후 각각의 호출자에게 전달되므로, 큰 응답을 스트리밍해야 하는 경우에는 적합하지 않다.
+func WithDedup(headers ...string) Option {
+	return func(o *options) {
+		o.dedup = newDedup(headers)
+	}
+}
+
+// WithMaxBodySize는 응답 body의 크기를 maxSize 바이트로 제한한다. 압축된 응답은 압축이 해제된 크기를 제한한다.
+// 제한을 넘으면 요청 혹은 body를 읽을 때 *BodyTooLargeError를 반환한다.
+func WithMaxBodySize(maxSize int64) Option {
+	return func(o *options) {
+		o.body.maxSize = maxSize
+	}
+}
+
+// WithDecompression은 RegisterDecoder로 등록된 Content-Encoding들을 Accept-Encoding 헤더로 보내고, 압축된 응답 body를 해제하여 반환한다.
+func WithDecompression() Option {
+	return func(o *options) {
+		o.body.decompress = true
+	}
+}
+
+// WithCharsetConversion은 text, xml 응답의 body를 Content-Type 헤더나 HTML의 meta 태그에 지정된 charset에서 UTF-8로 변환한다.
+func WithCharsetConversion() Option {
+	return func(o *options) {
+		o.body.charset = true
+	}
+}
+
+// WithSession은 ApiActor의 모든 요청에 session의 cookie jar, 기본 header, Authenticator를 적용한다.
+// 401 응답을 받으면 Authenticator.Refresh로 인증 정보를 갱신하고 요청을 한번 더 보낸다. CallApi에는 적용되지 않는다.
+func WithSession(session *Session) Option {
+	return func(o *options) {
+		o.session = session
+	}
+}
@@ -0,0 +1,67 @@
+package apiactor_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func stubTransport(body string) roundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	t.Run("CallApi는 지정한 Transport로 요청을 전송한다.", func(t *testing.T) {
+		httpReq, err := http.NewRequest("GET", "http://stub.invalid", nil)
+		require.NoError(t, err)
+
+		rc, err := apiactor.CallApi(httpReq, apiactor.WithTransport(stubTransport("from CallApi")))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "from CallApi", string(body))
+	})
+
+	t.Run("ApiActor는 지정한 Transport로 요청을 전송한다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithTransport(stubTransport("from ApiActor")))
+
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", "http://stub.invalid"))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "from ApiActor", string(body))
+	})
+}
+
+func TestWithHttpClient(t *testing.T) {
+	server, _ := newTestServer(t, slowHandler(200*time.Millisecond, nil))
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+
+	apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithHttpClient(client))
+
+	_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+	require.Error(t, err)
+	require.False(t, apiactor.IsHttpError(err))
+}
@@ -0,0 +1,152 @@
+package apiactor
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jae2274/goutils/cchan"
+)
+
+// NextPageFunc는 r에 대한 응답으로부터 항목들과 다음 페이지의 요청을 추출한다. 마지막 페이지라면 next는 nil이다.
+// res.Body는 NextPageFunc가 반환된 후 Paginate가 닫는다.
+type NextPageFunc[T any] func(r *Request, res *Response) (items []T, next *Request, err error)
+
+// Paginate는 first부터 다음 페이지가 없을 때까지 ApiActor를 통해 차례로 요청을 보내고, 추출한 항목들을 반환하는 채널로 전달한다.
+// 에러가 발생하면 에러 채널로 전달하고 종료하며, 종료되면 두 채널 모두 닫힌다. 반환되는 채널들은 pipe.Transform의 입력으로 사용할 수 있다.
+// ctx가 종료되면 실행중인 요청을 취소하고 에러 없이 종료한다.
+func Paginate[T any](ctx context.Context, a *ApiActor, first *Request, bufferSize *int, nextPage NextPageFunc[T]) (<-chan T, <-chan error) {
+	bfs := 0
+	if bufferSize != nil {
+		bfs = *bufferSize
+	}
+
+	itemChan := make(chan T, bfs)
+	errChan := make(chan error, 1) // 에러는 최대 한번 전달되므로, 에러 채널을 읽지 않더라도 종료된다.
+
+	go func() {
+		defer close(errChan)
+		defer close(itemChan)
+
+		for r := first; r != nil; {
+			items, next, err := fetchPage(ctx, a, r, nextPage)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			for _, item := range items {
+				if ok := cchan.Send(ctx, itemChan, item); !ok {
+					return
+				}
+			}
+			r = next
+		}
+	}()
+
+	return itemChan, errChan
+}
+
+func fetchPage[T any](ctx context.Context, a *ApiActor, r *Request, nextPage NextPageFunc[T]) ([]T, *Request, error) {
+	res, err := a.CallResponse(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	return nextPage(r, res)
+}
+
+// ItemsFunc는 응답으로부터 항목들을 추출한다.
+type ItemsFunc[T any] func(res *Response) ([]T, error)
+
+// PageNumber는 query의 param에 페이지 번호를 1씩 증가시키며 요청하고, 항목이 없는 페이지를 받으면 종료한다.
+// 첫 페이지의 번호는 first의 Query 혹은 Url의 query에 설정된 값이며, 설정되지 않았다면 1이다.
+func PageNumber[T any](param string, items ItemsFunc[T]) NextPageFunc[T] {
+	return func(r *Request, res *Response) ([]T, *Request, error) {
+		pageItems, err := items(res)
+		if err != nil || len(pageItems) == 0 {
+			return pageItems, nil, err
+		}
+
+		page := 1
+		if value := r.queryValue(param); value != "" {
+			if page, err = strconv.Atoi(value); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return pageItems, r.Clone().SetQuery(param, strconv.Itoa(page+1)), nil
+	}
+}
+
+// Cursor는 응답으로부터 추출한 cursor를 query의 param에 설정하여 다음 페이지를 요청하며, cursor가 빈 문자열이면 종료한다.
+func Cursor[T any](param string, extract func(res *Response) (items []T, cursor string, err error)) NextPageFunc[T] {
+	return func(r *Request, res *Response) ([]T, *Request, error) {
+		items, cursor, err := extract(res)
+		if err != nil || cursor == "" {
+			return items, nil, err
+		}
+
+		return items, r.Clone().SetQuery(param, cursor), nil
+	}
+}
+
+// LinkHeader는 응답의 Link 헤더에서 rel="next"인 URL로 다음 페이지를 요청하며, 없으면 종료한다.
+func LinkHeader[T any](items ItemsFunc[T]) NextPageFunc[T] {
+	return func(r *Request, res *Response) ([]T, *Request, error) {
+		pageItems, err := items(res)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		nextUrl, ok := NextLink(res.Header.Values("Link"))
+		if !ok {
+			return pageItems, nil, nil
+		}
+
+		base, err := url.Parse(r.Url)
+		if err != nil {
+			return nil, nil, err
+		}
+		ref, err := url.Parse(nextUrl)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		next := r.Clone()
+		next.Url = base.ResolveReference(ref).String()
+		next.Query = make(url.Values) // 다음 페이지의 URL에 query가 모두 포함되어 있다.
+		return pageItems, next, nil
+	}
+}
+
+// NextLink는 RFC 8288 형식의 Link 헤더 값들에서 rel="next"인 URL을 찾는다.
+func NextLink(links []string) (string, bool) {
+	for _, link := range links {
+		for _, part := range strings.Split(link, ",") {
+			target, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.Trim(target, "<>"), true
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
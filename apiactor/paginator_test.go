@@ -0,0 +1,158 @@
+package apiactor_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/jae2274/goutils/cchan"
+	"github.com/jae2274/goutils/cchan/pipe"
+	"github.com/jae2274/goutils/jjson"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedHandler는 1부터 total까지의 숫자를 pageSize개씩 나누어 응답한다.
+func pagedHandler(total, pageSize int) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		page := 1
+		switch r.URL.Path {
+		case "/page", "/link":
+			if value := r.URL.Query().Get("page"); value != "" {
+				page, _ = strconv.Atoi(value)
+			}
+		case "/cursor":
+			if value := r.URL.Query().Get("cursor"); value != "" {
+				page, _ = strconv.Atoi(value)
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		items := []int{}
+		for i := (page-1)*pageSize + 1; i <= min(page*pageSize, total); i++ {
+			items = append(items, i)
+		}
+
+		hasNext := page*pageSize < total
+		switch r.URL.Path {
+		case "/link":
+			if hasNext {
+				w.Header().Add("Link", fmt.Sprintf(`<%s/link?page=1>; rel="first", </link?page=%d>; rel="next"`, "http://"+r.Host, page+1))
+			}
+		case "/cursor":
+			cursor := ""
+			if hasNext {
+				cursor = strconv.Itoa(page + 1)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"items": items, "cursor": cursor})
+			return
+		}
+		json.NewEncoder(w).Encode(items)
+	}
+}
+
+func decodeItems(res *apiactor.Response) ([]int, error) {
+	items, err := jjson.UnmarshalReader[[]int](res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return *items, nil
+}
+
+func TestPaginate(t *testing.T) {
+	server, _ := newTestServer(t, pagedHandler(7, 3))
+	apiActor := apiactor.NewApiActor(context.Background(), 0)
+	expected := []int{1, 2, 3, 4, 5, 6, 7}
+
+	t.Run("페이지 번호를 증가시키며 항목이 없는 페이지까지 요청한다.", func(t *testing.T) {
+		itemChan, errChan := apiactor.Paginate(context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/page"), nil,
+			apiactor.PageNumber("page", decodeItems))
+
+		require.Equal(t, expected, cchan.WaitClosed(itemChan))
+		require.Empty(t, cchan.WaitClosed(errChan))
+	})
+
+	t.Run("첫 페이지의 번호가 Url에 있으면 그 다음 페이지부터 요청한다.", func(t *testing.T) {
+		itemChan, errChan := apiactor.Paginate(context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/page?page=2"), nil,
+			apiactor.PageNumber("page", decodeItems))
+
+		require.Equal(t, []int{4, 5, 6, 7}, cchan.WaitClosed(itemChan))
+		require.Empty(t, cchan.WaitClosed(errChan))
+	})
+
+	t.Run("응답의 cursor로 다음 페이지를 요청한다.", func(t *testing.T) {
+		type cursorPage struct {
+			Items  []int  `json:"items"`
+			Cursor string `json:"cursor"`
+		}
+
+		itemChan, errChan := apiactor.Paginate(context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/cursor"), nil,
+			apiactor.Cursor("cursor", func(res *apiactor.Response) ([]int, string, error) {
+				page, err := jjson.UnmarshalReader[cursorPage](res.Body)
+				if err != nil {
+					return nil, "", err
+				}
+				return page.Items, page.Cursor, nil
+			}))
+
+		require.Equal(t, expected, cchan.WaitClosed(itemChan))
+		require.Empty(t, cchan.WaitClosed(errChan))
+	})
+
+	t.Run("Link 헤더의 rel=\"next\" URL로 다음 페이지를 요청하며, pipe.Transform으로 이어서 처리할 수 있다.", func(t *testing.T) {
+		ctx := context.Background()
+		itemChan, errChan := apiactor.Paginate(ctx, apiActor, apiactor.NewRequest("GET", server.URL+"/link"), nil,
+			apiactor.LinkHeader(decodeItems))
+
+		doubledChan, transformErrChan := pipe.Transform(ctx, itemChan, nil, func(item int) (int, error) {
+			return item * 2, nil
+		})
+
+		require.Equal(t, []int{2, 4, 6, 8, 10, 12, 14}, cchan.WaitClosed(doubledChan))
+		require.Empty(t, cchan.WaitClosed(errChan))
+		require.Empty(t, cchan.WaitClosed(transformErrChan))
+	})
+
+	t.Run("요청이 실패하면 에러를 전달하고 종료한다.", func(t *testing.T) {
+		itemChan, errChan := apiactor.Paginate(context.Background(), apiActor, apiactor.NewRequest("GET", server.URL+"/page"), nil,
+			func(r *apiactor.Request, res *apiactor.Response) ([]int, *apiactor.Request, error) {
+				items, err := decodeItems(res)
+				return items, apiactor.NewRequest("GET", server.URL+"/missing"), err
+			})
+
+		require.Equal(t, []int{1, 2, 3}, cchan.WaitClosed(itemChan))
+		errs := cchan.WaitClosed(errChan)
+		require.Len(t, errs, 1)
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(errs[0], http.StatusNotFound))
+	})
+
+	t.Run("ctx가 종료되면 에러 없이 종료한다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		itemChan, errChan := apiactor.Paginate(ctx, apiActor, apiactor.NewRequest("GET", server.URL+"/page"), nil,
+			apiactor.PageNumber("page", decodeItems))
+
+		first := <-itemChan
+		require.Equal(t, 1, first)
+		cancel()
+
+		require.Eventually(t, func() bool {
+			closed, _ := cchan.IsClosed(errChan)
+			return closed
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestNextLink(t *testing.T) {
+	next, ok := apiactor.NextLink([]string{`<https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=3>; rel="next last"`})
+	require.True(t, ok)
+	require.Equal(t, "https://api.example.com/items?page=3", next)
+
+	_, ok = apiactor.NextLink([]string{`<https://api.example.com/items?page=1>; rel="prev"`})
+	require.False(t, ok)
+}
@@ -0,0 +1,108 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type orderRecorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+// handler는 요청의 id query를 받은 순서대로 기록하고 delay 후에 응답한다.
+func (recorder *orderRecorder) handler(delay time.Duration) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		recorder.mu.Lock()
+		recorder.order = append(recorder.order, r.URL.Query().Get("id"))
+		recorder.mu.Unlock()
+
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	}
+}
+
+// enqueueHook은 요청이 대기열에 들어갈 때마다 enqueued로 알린다.
+type enqueueHook struct {
+	enqueued chan struct{}
+}
+
+func newEnqueueHook() enqueueHook {
+	return enqueueHook{enqueued: make(chan struct{}, 1)}
+}
+
+func (h enqueueHook) OnEnqueue(info apiactor.CallInfo) { h.enqueued <- struct{}{} }
+func (h enqueueHook) OnStart(info apiactor.CallInfo)   {}
+func (h enqueueHook) OnFinish(info apiactor.CallInfo)  {}
+
+// 첫 요청이 실행되는 동안 나머지 요청들을 interval 간격으로 대기열에 넣고, 모든 요청이 끝날 때까지 기다린다.
+// 요청이 대기열에 들어간 것을 hook으로 확인한 후에 다음 요청을 보내므로, 요청들은 항상 reqs의 순서대로 대기열에 들어간다.
+func callInOrder(t *testing.T, apiActor *apiactor.ApiActor, hook enqueueHook, interval time.Duration, reqs ...*apiactor.Request) {
+	wg := sync.WaitGroup{}
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(req *apiactor.Request) {
+			defer wg.Done()
+			_, err := apiActor.Call(req)
+			require.NoError(t, err)
+		}(req)
+		<-hook.enqueued
+		time.Sleep(interval)
+	}
+	wg.Wait()
+}
+
+func TestPriority(t *testing.T) {
+	t.Run("Priority가 높은 요청이 먼저 실행되고, 같은 Priority는 먼저 들어온 순서대로 실행된다.", func(t *testing.T) {
+		recorder := &orderRecorder{}
+		server, _ := newTestServer(t, recorder.handler(200*time.Millisecond))
+		hook := newEnqueueHook()
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithHooks(hook))
+
+		callInOrder(t, apiActor, hook, 0,
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "first"),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "low1").SetPriority(apiactor.PriorityLow),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "normal"),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "low2").SetPriority(apiactor.PriorityLow),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "high").SetPriority(apiactor.PriorityHigh),
+		)
+
+		require.Equal(t, []string{"first", "high", "normal", "low1", "low2"}, recorder.order)
+	})
+
+	t.Run("오래 대기한 요청은 Priority가 올라가 나중에 들어온 높은 Priority의 요청보다 먼저 실행될 수 있다.", func(t *testing.T) {
+		recorder := &orderRecorder{}
+		server, _ := newTestServer(t, recorder.handler(400*time.Millisecond))
+		hook := newEnqueueHook()
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithPriorityAging(50*time.Millisecond), apiactor.WithHooks(hook))
+
+		callInOrder(t, apiActor, hook, 150*time.Millisecond,
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "first"),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "old").SetPriority(0),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "new").SetPriority(1),
+		)
+
+		require.Equal(t, []string{"first", "old", "new"}, recorder.order)
+	})
+
+	t.Run("aging을 사용하지 않으면 Priority만으로 순서가 결정된다.", func(t *testing.T) {
+		recorder := &orderRecorder{}
+		server, _ := newTestServer(t, recorder.handler(400*time.Millisecond))
+		hook := newEnqueueHook()
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithPriorityAging(0), apiactor.WithHooks(hook))
+
+		callInOrder(t, apiActor, hook, 150*time.Millisecond,
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "first"),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "old").SetPriority(0),
+			apiactor.NewRequest("GET", server.URL).AddQuery("id", "new").SetPriority(1),
+		)
+
+		require.Equal(t, []string{"first", "new", "old"}, recorder.order)
+	})
+}
@@ -0,0 +1,196 @@
+package apiactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Request struct {
+	Method string
+	Url    string
+	Header http.Header
+	// Query의 key가 Url의 query에도 있으면, Url의 값들은 Query의 값들로 대체된다.
+	Query url.Values
+	// Body는 호출될 때마다 새로운 reader를 반환해야 한다. 재시도 등으로 같은 요청이 여러번 전송될 수 있기 때문이다.
+	Body func() (io.Reader, error)
+	// Priority가 높은 요청이 먼저 실행되며, 같은 Priority의 요청은 먼저 들어온 순서대로 실행된다.
+	Priority int
+	// AcceptStatus가 nil이 아니면 ApiActor의 WithAcceptStatus 옵션 대신 사용된다.
+	AcceptStatus func(statusCode int) bool
+	// Idempotent가 true이면 POST처럼 멱등이 아닌 method의 요청도 중복 전송되어도 안전한 것으로 보고 네트워크 에러시 재시도한다.
+	Idempotent bool
+}
+
+const (
+	PriorityLow    = -10
+	PriorityNormal = 0
+	PriorityHigh   = 10
+)
+
+func NewRequest(method string, url string) *Request {
+	r := &Request{
+		Method: method,
+		Url:    url,
+		Header: make(http.Header),
+		Query:  make(map[string][]string),
+	}
+
+	return r
+}
+
+// Clone은 Header와 Query를 복사한 새로운 Request를 반환한다. Body 함수는 공유된다.
+func (r *Request) Clone() *Request {
+	cloned := *r
+	cloned.Header = r.Header.Clone()
+	cloned.Query = url.Values(http.Header(r.Query).Clone())
+	return &cloned
+}
+
+func (r *Request) AddQuery(key, value string) *Request {
+	if r.Query == nil {
+		r.Query = make(url.Values)
+	}
+	r.Query.Add(key, value)
+	return r
+}
+
+func (r *Request) SetQuery(key, value string) *Request {
+	if r.Query == nil {
+		r.Query = make(url.Values)
+	}
+	r.Query.Set(key, value)
+	return r
+}
+
+func (r *Request) SetHeader(key, value string) *Request {
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	r.Header.Set(key, value)
+	return r
+}
+
+func (r *Request) SetPriority(priority int) *Request {
+	r.Priority = priority
+	return r
+}
+
+func (r *Request) SetAcceptStatus(acceptStatus func(statusCode int) bool) *Request {
+	r.AcceptStatus = acceptStatus
+	return r
+}
+
+func (r *Request) SetIdempotent(idempotent bool) *Request {
+	r.Idempotent = idempotent
+	return r
+}
+
+// isIdempotent는 요청이 서버에 전달되었는지 알 수 없을 때, 다시 보내도 안전한지 여부를 반환한다.
+func (r *Request) isIdempotent() bool {
+	switch strings.ToUpper(r.Method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return r.Idempotent
+	}
+}
+
+func (r *Request) SetBody(body []byte) *Request {
+	r.Body = func() (io.Reader, error) {
+		return bytes.NewReader(body), nil
+	}
+	return r
+}
+
+func (r *Request) SetBodyFunc(bodyFunc func() (io.Reader, error)) *Request {
+	r.Body = bodyFunc
+	return r
+}
+
+func (r *Request) SetFormBody(form url.Values) *Request {
+	return r.SetHeader("Content-Type", "application/x-www-form-urlencoded").SetBody([]byte(form.Encode()))
+}
+
+func (r *Request) SetJsonBody(value any) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	r.SetHeader("Content-Type", "application/json").SetBody(body)
+	return nil
+}
+
+func (r *Request) fullUrl() (string, error) {
+	if len(r.Query) == 0 {
+		return r.Url, nil
+	}
+
+	u, err := url.Parse(r.Url)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for key, values := range r.Query {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// queryValue는 Query에 key가 있으면 Query의 값을, 없으면 Url의 query의 값을 반환한다.
+func (r *Request) queryValue(key string) string {
+	if values, ok := r.Query[key]; ok && len(values) > 0 {
+		return values[0]
+	}
+
+	u, err := url.Parse(r.Url)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(key)
+}
+
+func converthttpReq(ctx context.Context, r *Request) (*http.Request, error) {
+	fullUrl, err := r.fullUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if r.Body != nil {
+		body, err = r.Body()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, strings.ToUpper(r.Method), fullUrl, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Body != nil {
+		httpReq.GetBody = func() (io.ReadCloser, error) {
+			body, err := r.Body()
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(body), nil
+		}
+	}
+
+	httpReq.Header = r.Header.Clone()
+	if httpReq.Header == nil {
+		httpReq.Header = make(http.Header)
+	}
+
+	return httpReq, nil
+}
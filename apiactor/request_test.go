@@ -0,0 +1,84 @@
+package apiactor_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+type echoed struct {
+	method      string
+	query       url.Values
+	contentType string
+	body        string
+}
+
+func TestRequestBody(t *testing.T) {
+	echoedChan := make(chan echoed, 100)
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		echoedChan <- echoed{r.Method, r.URL.Query(), r.Header.Get("Content-Type"), string(body)}
+		w.Write([]byte("ok"))
+	})
+	apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+	t.Run("JSON body를 설정하면 Content-Type과 함께 전송된다.", func(t *testing.T) {
+		req := apiactor.NewRequest("POST", server.URL)
+		require.NoError(t, req.SetJsonBody(map[string]string{"keyword": "golang"}))
+
+		_, err := apiActor.Call(req)
+		require.NoError(t, err)
+
+		e := <-echoedChan
+		require.Equal(t, "POST", e.method)
+		require.Equal(t, "application/json", e.contentType)
+		require.JSONEq(t, `{"keyword":"golang"}`, e.body)
+	})
+
+	t.Run("form body와 query가 함께 전송된다.", func(t *testing.T) {
+		req := apiactor.NewRequest("put", server.URL+"?page=1").
+			AddQuery("size", "20").
+			AddQuery("tag", "a").
+			AddQuery("tag", "b").
+			SetFormBody(url.Values{"keyword": {"golang"}})
+
+		_, err := apiActor.Call(req)
+		require.NoError(t, err)
+
+		e := <-echoedChan
+		require.Equal(t, "PUT", e.method)
+		require.Equal(t, url.Values{"page": {"1"}, "size": {"20"}, "tag": {"a", "b"}}, e.query)
+		require.Equal(t, "application/x-www-form-urlencoded", e.contentType)
+		require.Equal(t, "keyword=golang", e.body)
+	})
+
+	t.Run("Query의 key가 Url에도 있으면 Url의 값을 대체한다.", func(t *testing.T) {
+		req := apiactor.NewRequest("GET", server.URL+"?page=1&size=20").SetQuery("page", "2")
+
+		_, err := apiActor.Call(req)
+		require.NoError(t, err)
+
+		e := <-echoedChan
+		require.Equal(t, url.Values{"page": {"2"}, "size": {"20"}}, e.query)
+	})
+
+	t.Run("같은 Request를 여러번 호출해도 body가 매번 전송된다.", func(t *testing.T) {
+		req := apiactor.NewRequest("PATCH", server.URL).SetBody([]byte("raw"))
+
+		for i := 0; i < 2; i++ {
+			_, err := apiActor.Call(req)
+			require.NoError(t, err)
+
+			e := <-echoedChan
+			require.Equal(t, "PATCH", e.method)
+			require.Equal(t, "raw", e.body)
+		}
+	})
+}
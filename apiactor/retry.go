@@ -0,0 +1,109 @@
+package apiactor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type RetryPolicy interface {
+	// NextDelay는 attempt번째 시도가 err로 실패했을 때, 다음 시도까지의 대기시간과 재시도 여부를 반환한다.
+	// ApiActor는 err를 *RequestError로 감싸서 전달한다.
+	// attempt는 1부터 시작한다.
+	NextDelay(attempt int, err error) (time.Duration, bool)
+}
+
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type BackoffRetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64 // 0~1 사이의 값으로, 대기시간에 ±Jitter 비율만큼의 무작위 편차를 준다.
+	RetryableStatusCodes []int
+}
+
+func NewBackoffRetryPolicy(maxAttempts int) *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts:          maxAttempts,
+		BaseDelay:            500 * time.Millisecond,
+		MaxDelay:             30 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: DefaultRetryableStatusCodes,
+	}
+}
+
+func (p *BackoffRetryPolicy) NextDelay(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !p.isRetryable(err) {
+		return 0, false
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	backoff := time.Duration(delay)
+	if retryAfter, ok := retryAfter(err); ok && retryAfter > backoff {
+		backoff = retryAfter
+	}
+
+	return backoff, true
+}
+
+func (p *BackoffRetryPolicy) isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrBodyTooLarge) {
+		return false
+	}
+
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return slices.Contains(p.RetryableStatusCodes, httpErr.StatusCode)
+	}
+
+	// 응답을 받지 못한 네트워크 에러는 서버가 요청을 처리했는지 알 수 없으므로, 멱등인 요청만 재시도한다.
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Idempotent
+	}
+	return true
+}
+
+func retryAfter(err error) (time.Duration, bool) {
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) || httpErr.Header == nil {
+		return 0, false
+	}
+
+	return parseRetryAfter(httpErr.Header.Get("Retry-After"))
+}
+
+// Retry-After 헤더는 초 단위의 정수 또는 HTTP-date 형식을 가진다.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds >= 0
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package apiactor_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyHandler는 처음 failCount번의 요청에 failStatus와 header로 응답한다.
+func flakyHandler(failCount int32, failStatus int, header http.Header) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		if count <= failCount {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.Write([]byte("ok"))
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := &apiactor.BackoffRetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            10 * time.Millisecond,
+		MaxDelay:             time.Second,
+		RetryableStatusCodes: apiactor.DefaultRetryableStatusCodes,
+	}
+
+	t.Run("재시도 가능한 status code는 MaxAttempts까지 재시도한다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(2, http.StatusServiceUnavailable, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, int32(3), called.Load())
+	})
+
+	t.Run("MaxAttempts를 초과하면 마지막 에러를 반환한다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(5, http.StatusBadGateway, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusBadGateway))
+		require.Equal(t, int32(3), called.Load())
+	})
+
+	t.Run("재시도 불가능한 status code는 재시도하지 않는다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(5, http.StatusNotFound, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusNotFound))
+		require.Equal(t, int32(1), called.Load())
+	})
+
+	t.Run("Retry-After 헤더가 존재하면 그만큼 대기한 후 재시도한다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}))
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		start := time.Now()
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), called.Load())
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("재시도 간격은 minDelay보다 짧지 않다.", func(t *testing.T) {
+		server, called := newTestServer(t, flakyHandler(1, http.StatusServiceUnavailable, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 300, apiactor.WithRetryPolicy(policy))
+
+		start := time.Now()
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), called.Load())
+		require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+	})
+}
+
+func TestRetryPolicyNetworkError(t *testing.T) {
+	policy := &apiactor.BackoffRetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond}
+
+	// 요청 body를 읽은 후 응답하지 않고 연결을 끊는다.
+	server, called := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		io.ReadAll(r.Body)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	})
+
+	t.Run("멱등이 아닌 요청은 네트워크 에러가 발생해도 재시도하지 않는다.", func(t *testing.T) {
+		called.Store(0)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		_, err := apiActor.Call(apiactor.NewRequest("POST", server.URL).SetBody([]byte("submit")))
+		require.True(t, apiactor.IsRequestError(err))
+		require.Equal(t, int32(1), called.Load())
+	})
+
+	t.Run("Idempotent를 설정한 요청은 네트워크 에러가 발생하면 재시도한다.", func(t *testing.T) {
+		called.Store(0)
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithRetryPolicy(policy))
+
+		_, err := apiActor.Call(apiactor.NewRequest("POST", server.URL).SetBody([]byte("submit")).SetIdempotent(true))
+		require.True(t, apiactor.IsRequestError(err))
+		require.Equal(t, int32(3), called.Load())
+	})
+}
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	policy := &apiactor.BackoffRetryPolicy{
+		MaxAttempts:          5,
+		BaseDelay:            100 * time.Millisecond,
+		MaxDelay:             300 * time.Millisecond,
+		RetryableStatusCodes: apiactor.DefaultRetryableStatusCodes,
+	}
+	err := &apiactor.HttpError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+
+	for attempt, expected := range []time.Duration{100, 200, 300, 300} {
+		delay, ok := policy.NextDelay(attempt+1, err)
+		require.True(t, ok)
+		require.Equal(t, expected*time.Millisecond, delay)
+	}
+
+	_, ok := policy.NextDelay(5, err)
+	require.False(t, ok)
+
+	_, ok = policy.NextDelay(1, context.Canceled)
+	require.False(t, ok)
+
+	_, ok = policy.NextDelay(1, errors.New("connection reset"))
+	require.True(t, ok)
+
+	_, ok = policy.NextDelay(1, &apiactor.RequestError{Method: "POST", Err: errors.New("connection reset")})
+	require.False(t, ok)
+
+	_, ok = policy.NextDelay(1, &apiactor.RequestError{Method: "PUT", Err: errors.New("connection reset"), Idempotent: true})
+	require.True(t, ok)
+}
@@ -0,0 +1,20 @@
+package apiactor_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestServer는 handler로 응답하는 테스트 서버와 받은 요청의 수를 반환하며, 서버는 테스트가 끝나면 종료된다.
+// handler에는 몇번째 요청인지 1부터 함께 전달된다.
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, count int32)) (*httptest.Server, *atomic.Int32) {
+	called := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, called.Add(1))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, called
+}
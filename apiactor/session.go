@@ -0,0 +1,151 @@
+package apiactor
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Authenticator는 요청에 인증 정보를 설정하며, 여러 goroutine에서 동시에 호출될 수 있다.
+type Authenticator interface {
+	// Authenticate는 요청을 보내기 전에 호출되어 Authorization 헤더 등을 설정한다.
+	Authenticate(ctx context.Context, httpReq *http.Request) error
+	// Refresh는 401 응답을 받았을 때 호출되어 인증 정보를 갱신한다. Refresh가 성공하면 요청을 한번 더 보낸다.
+	Refresh(ctx context.Context) error
+}
+
+// Session은 ApiActor의 모든 요청이 공유하는 cookie, 기본 header, 인증 정보이다.
+type Session struct {
+	Jar           http.CookieJar
+	Header        http.Header // 요청에 설정되지 않은 header만 추가된다.
+	Authenticator Authenticator
+
+	// 동시에 여러 요청이 401 응답을 받더라도 한번만 갱신하기 위해, 갱신할 때마다 generation을 증가시킨다.
+	mu         sync.RWMutex
+	generation uint64
+
+	idOnce sync.Once
+	id     uint64
+}
+
+var lastSessionId atomic.Uint64
+
+// NewSession은 cookiejar.Jar를 사용하는 Session을 생성한다.
+func NewSession() *Session {
+	jar, _ := cookiejar.New(nil) // cookiejar.New는 에러를 반환하지 않는다.
+	return &Session{
+		Jar:    jar,
+		Header: make(http.Header),
+	}
+}
+
+func (s *Session) SetHeader(key, value string) *Session {
+	if s.Header == nil {
+		s.Header = make(http.Header)
+	}
+	s.Header.Set(key, value)
+	return s
+}
+
+func (s *Session) SetAuthenticator(authenticator Authenticator) *Session {
+	s.Authenticator = authenticator
+	return s
+}
+
+// prepare는 기본 header와 인증 정보를 설정하고, 인증 정보의 generation을 반환한다.
+func (s *Session) prepare(ctx context.Context, httpReq *http.Request) (uint64, error) {
+	if s == nil {
+		return 0, nil
+	}
+
+	for key, values := range s.Header {
+		if _, ok := httpReq.Header[key]; !ok {
+			httpReq.Header[key] = slices.Clone(values)
+		}
+	}
+
+	if s.Authenticator == nil {
+		return 0, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.generation, s.Authenticator.Authenticate(ctx, httpReq)
+}
+
+// key는 응답이 다른 Session의 요청에 사용되지 않도록 cache의 key에 추가할 문자열을 반환한다.
+func (s *Session) key() string {
+	if s == nil {
+		return ""
+	}
+
+	s.idOnce.Do(func() { s.id = lastSessionId.Add(1) })
+	return "\nsession: " + strconv.FormatUint(s.id, 10)
+}
+
+func (s *Session) canRefresh() bool {
+	return s != nil && s.Authenticator != nil
+}
+
+// refresh는 generation의 인증 정보로 보낸 요청이 401 응답을 받았을 때 호출된다.
+// 그 사이에 다른 요청이 이미 갱신했다면 다시 갱신하지 않는다.
+func (s *Session) refresh(ctx context.Context, generation uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generation != generation {
+		return nil
+	}
+
+	if err := s.Authenticator.Refresh(ctx); err != nil {
+		return err
+	}
+	s.generation++
+	return nil
+}
+
+// BearerAuthenticator는 fetchToken으로 발급받은 token을 Authorization: Bearer 헤더로 보내며, 401 응답을 받으면 token을 다시 발급받는다.
+type BearerAuthenticator struct {
+	fetchToken func(ctx context.Context) (string, error)
+
+	mu    sync.Mutex
+	token string
+}
+
+func NewBearerAuthenticator(fetchToken func(ctx context.Context) (string, error)) *BearerAuthenticator {
+	return &BearerAuthenticator{fetchToken: fetchToken}
+}
+
+func (a *BearerAuthenticator) Authenticate(ctx context.Context, httpReq *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" { // 처음 요청할 때 token을 발급받는다.
+		token, err := a.fetchToken(ctx)
+		if err != nil {
+			return err
+		}
+		a.token = token
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+func (a *BearerAuthenticator) Refresh(ctx context.Context) error {
+	token, err := a.fetchToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = token
+	return nil
+}
@@ -0,0 +1,99 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionHandler는 /login에서 session cookie를 발급하고, /me와 /token에서는 cookie와 validToken으로 인증한다.
+func sessionHandler(validToken string) func(w http.ResponseWriter, r *http.Request, count int32) {
+	return func(w http.ResponseWriter, r *http.Request, count int32) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+		case "/me":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "s1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(r.Header.Get("User-Agent") + " " + r.Header.Get("Accept-Language")))
+		case "/token":
+			if r.Header.Get("Authorization") != "Bearer "+validToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("ok"))
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	t.Run("cookie와 기본 header가 모든 요청에 적용되며, 요청에 설정된 header가 우선한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, sessionHandler(""))
+		session := apiactor.NewSession().
+			SetHeader("User-Agent", "careerhub").
+			SetHeader("Accept-Language", "ko")
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithSession(session))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/me"))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusForbidden))
+
+		_, err = apiActor.Call(apiactor.NewRequest("POST", server.URL+"/login"))
+		require.NoError(t, err)
+
+		rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/me"))
+		require.NoError(t, err)
+		require.Equal(t, "careerhub ko", readAll(t, rc))
+
+		rc, err = apiActor.Call(apiactor.NewRequest("GET", server.URL+"/me").SetHeader("Accept-Language", "en"))
+		require.NoError(t, err)
+		require.Equal(t, "careerhub en", readAll(t, rc))
+	})
+
+	t.Run("401 응답을 받으면 인증 정보를 갱신하여 한번 더 요청하며, 동시에 실패한 요청들은 한번만 갱신한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, sessionHandler("t2"))
+		fetched := &atomic.Int32{}
+		authenticator := apiactor.NewBearerAuthenticator(func(ctx context.Context) (string, error) {
+			return "t" + strconv.Itoa(int(fetched.Add(1))), nil
+		})
+		apiActor := apiactor.NewApiActor(context.Background(), 0,
+			apiactor.WithSession(apiactor.NewSession().SetAuthenticator(authenticator)),
+			apiactor.WithLimiter(apiactor.NewTokenBucketLimiter(100, 100, 0)),
+		)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rc, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/token"))
+				require.NoError(t, err)
+				require.Equal(t, "ok", readAll(t, rc))
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(2), fetched.Load())
+	})
+
+	t.Run("갱신한 후에도 401 응답을 받으면 HttpError를 반환한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, sessionHandler("never"))
+		fetched := &atomic.Int32{}
+		authenticator := apiactor.NewBearerAuthenticator(func(ctx context.Context) (string, error) {
+			return "t" + strconv.Itoa(int(fetched.Add(1))), nil
+		})
+		apiActor := apiactor.NewApiActor(context.Background(), 0, apiactor.WithSession(apiactor.NewSession().SetAuthenticator(authenticator)))
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", server.URL+"/token"))
+		require.True(t, apiactor.IsHttpErrorWithStatusCode(err, http.StatusUnauthorized))
+		require.Equal(t, int32(2), fetched.Load())
+	})
+}
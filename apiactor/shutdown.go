@@ -0,0 +1,76 @@
+package apiactor
+
+import (
+	"context"
+)
+
+type ShutdownReport struct {
+	Completed int // Shutdown이 시작된 이후 실행이 끝난 요청의 수
+	Abandoned int // ErrActorStopped로 거절된 요청의 수
+}
+
+type shutdownRequest struct {
+	ctx        context.Context
+	resultChan chan shutdownResult
+	expired    bool
+
+	completedBefore int64
+	abandonedBefore int64
+}
+
+type shutdownResult struct {
+	report ShutdownReport
+	err    error
+}
+
+func (s *shutdownRequest) start(a *ApiActor) {
+	close(a.stopped)
+	s.completedBefore = a.completed.Load()
+	s.abandonedBefore = a.abandoned.Load()
+}
+
+// deadline은 Shutdown의 기한이 지나기 전까지 ctx.Done()을 반환하며, 기한이 지난 이후나 Shutdown 전에는 nil 채널을 반환한다.
+func (s *shutdownRequest) deadline() <-chan struct{} {
+	if s == nil || s.expired {
+		return nil
+	}
+	return s.ctx.Done()
+}
+
+func (s *shutdownRequest) finish(a *ApiActor, err error) {
+	if err == nil && s.expired {
+		err = s.ctx.Err()
+	}
+
+	s.resultChan <- shutdownResult{
+		report: ShutdownReport{
+			Completed: int(a.completed.Load() - s.completedBefore),
+			Abandoned: int(a.abandoned.Load() - s.abandonedBefore),
+		},
+		err: err,
+	}
+}
+
+// Shutdown은 새로운 요청을 더이상 받지 않고, 대기중이거나 실행중인 요청이 모두 끝날 때까지 기다린다.
+// ctx가 종료되면 남은 요청들은 ErrActorStopped로 거절되고 실행중인 요청은 취소되며, ctx.Err()을 반환한다.
+// 이미 종료된 ApiActor이거나 동시에 호출된 다른 Shutdown이 종료를 진행중이면 ErrActorStopped를 반환한다.
+func (a *ApiActor) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	req := &shutdownRequest{ctx: ctx, resultChan: make(chan shutdownResult, 1)}
+
+	select {
+	case <-a.stopped:
+		return ShutdownReport{}, ErrActorStopped
+	default:
+	}
+
+	select {
+	case a.shutdownChan <- req:
+	case <-a.stopped:
+		return ShutdownReport{}, ErrActorStopped
+	case <-a.exited:
+		return ShutdownReport{}, ErrActorStopped
+	}
+
+	result := <-req.resultChan
+	return result.report, result.err
+}
@@ -0,0 +1,133 @@
+package apiactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/stretchr/testify/require"
+)
+
+// 요청들을 대기열에 넣고, 각 요청의 결과를 반환하는 채널을 반환한다.
+func callAsync(apiActor *apiactor.ApiActor, url string, count int) <-chan error {
+	errChan := make(chan error, count)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rc, err := apiActor.Call(apiactor.NewRequest("GET", url))
+			if err == nil {
+				rc.Close()
+			}
+			errChan <- err
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(errChan)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	return errChan
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("Shutdown은 대기중인 요청과 실행중인 요청이 모두 끝날 때까지 기다린다.", func(t *testing.T) {
+		server, _ := newTestServer(t, slowHandler(100*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		errChan := callAsync(apiActor, server.URL, 3)
+
+		report, err := apiActor.Shutdown(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, apiactor.ShutdownReport{Completed: 3, Abandoned: 0}, report)
+
+		for err := range errChan {
+			require.NoError(t, err)
+		}
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.ErrorIs(t, err, apiactor.ErrActorStopped)
+
+		_, err = apiActor.Shutdown(context.Background())
+		require.ErrorIs(t, err, apiactor.ErrActorStopped)
+	})
+
+	t.Run("Shutdown의 기한이 지나면 남은 요청들은 ErrActorStopped로 거절된다.", func(t *testing.T) {
+		server, _ := newTestServer(t, slowHandler(300*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		errChan := callAsync(apiActor, server.URL, 3)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		report, err := apiActor.Shutdown(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, apiactor.ShutdownReport{Completed: 0, Abandoned: 3}, report)
+		require.Less(t, time.Since(start), 250*time.Millisecond)
+
+		for err := range errChan {
+			require.ErrorIs(t, err, apiactor.ErrActorStopped)
+		}
+	})
+
+	t.Run("Shutdown 도중 ApiActor의 context가 종료되면 ErrActorStopped를 반환한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, slowHandler(300*time.Millisecond, nil))
+		ctx, cancel := context.WithCancel(context.Background())
+		apiActor := apiactor.NewApiActor(ctx, 0)
+
+		errChan := callAsync(apiActor, server.URL, 2)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+
+		report, err := apiActor.Shutdown(context.Background())
+		require.ErrorIs(t, err, apiactor.ErrActorStopped)
+		require.Equal(t, 1, report.Abandoned) // 대기중이던 요청만 거절되고, 실행중인 요청은 취소된다.
+
+		for err := range errChan {
+			require.ErrorIs(t, err, apiactor.ErrActorStopped)
+		}
+	})
+	t.Run("Shutdown이 동시에 호출되면 하나만 종료를 진행하고, 나머지는 ErrActorStopped를 반환한다.", func(t *testing.T) {
+		server, _ := newTestServer(t, slowHandler(100*time.Millisecond, nil))
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+
+		errChan := callAsync(apiActor, server.URL, 1)
+
+		start := make(chan struct{}) // 모든 goroutine이 최대한 동시에 Shutdown을 호출하도록 한다.
+		results := make(chan error, 64)
+		wg := sync.WaitGroup{}
+		for i := 0; i < cap(results); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, err := apiActor.Shutdown(context.Background())
+				results <- err
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(results)
+
+		succeeded := 0
+		for err := range results {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			require.ErrorIs(t, err, apiactor.ErrActorStopped)
+		}
+		require.Equal(t, 1, succeeded)
+		require.NoError(t, <-errChan)
+	})
+}
@@ -0,0 +1,50 @@
+package apiactor
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jae2274/goutils/mw"
+	"github.com/jae2274/goutils/mw/httpmw"
+)
+
+// ctxHeader는 context에 저장된 ctxKey의 값을 요청의 header로 전달한다.
+type ctxHeader struct {
+	ctxKey any
+	header string
+}
+
+// propagation은 httpmw, grpcmw가 context에 저장한 trace id 등을 외부로 보내는 요청에 전달한다.
+type propagation struct {
+	headers       []ctxHeader
+	generateTrace bool
+}
+
+func defaultPropagation() propagation {
+	return propagation{headers: []ctxHeader{{mw.CtxKeyTraceID, httpmw.XRequestId}}}
+}
+
+// context는 trace id를 생성하도록 설정되어 있고 ctx에 trace id가 없다면, 새로운 trace id를 저장한 context를 반환한다.
+// 재시도되는 요청들도 같은 trace id를 사용한다.
+func (p propagation) context(ctx context.Context) context.Context {
+	if !p.generateTrace {
+		return ctx
+	}
+	return mw.SetIfNotExists(ctx, mw.CtxKeyTraceID, uuid.New().String())
+}
+
+// apply는 httpReq의 context에 저장된 값들을 header로 설정한다. 문자열이 아닌 값과 이미 설정된 header는 무시한다.
+func (p propagation) apply(httpReq *http.Request) {
+	ctx := httpReq.Context()
+	for _, h := range p.headers {
+		value, ok := ctx.Value(h.ctxKey).(string)
+		if !ok || value == "" || httpReq.Header.Get(h.header) != "" {
+			continue
+		}
+		if httpReq.Header == nil {
+			httpReq.Header = make(http.Header)
+		}
+		httpReq.Header.Set(h.header, value)
+	}
+}
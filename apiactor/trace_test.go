@@ -0,0 +1,78 @@
+package apiactor_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jae2274/goutils/apiactor"
+	"github.com/jae2274/goutils/mw"
+	"github.com/jae2274/goutils/mw/httpmw"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKeyTenant struct{}
+
+func TestTraceId(t *testing.T) {
+	headerChan := make(chan http.Header, 100)
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, count int32) {
+		headerChan <- r.Header
+		w.Write([]byte("ok"))
+	})
+
+	t.Run("context의 trace id가 X-Request-Id 헤더로 전달된다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+		ctx := context.WithValue(context.Background(), mw.CtxKeyTraceID, "trace-1")
+
+		_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Equal(t, "trace-1", (<-headerChan).Get(httpmw.XRequestId))
+
+		_, err = apiActor.Call(apiactor.NewRequest("GET", server.URL))
+		require.NoError(t, err)
+		require.Empty(t, (<-headerChan).Get(httpmw.XRequestId))
+	})
+
+	t.Run("요청에 설정된 헤더는 덮어쓰지 않는다.", func(t *testing.T) {
+		apiActor := apiactor.NewApiActor(context.Background(), 0)
+		ctx := context.WithValue(context.Background(), mw.CtxKeyTraceID, "trace-1")
+
+		_, err := apiActor.CallContext(ctx, apiactor.NewRequest("GET", server.URL).SetHeader(httpmw.XRequestId, "manual"))
+		require.NoError(t, err)
+		require.Equal(t, "manual", (<-headerChan).Get(httpmw.XRequestId))
+	})
+
+	t.Run("WithGenerateTraceId를 지정하면 trace id가 없을 때 새로 생성하며, 재시도에도 같은 trace id를 사용한다.", func(t *testing.T) {
+		flakyServer, _ := newTestServer(t, flakyHandler(1, http.StatusServiceUnavailable, nil))
+		var traceIds []string
+		apiActor := apiactor.NewApiActor(context.Background(), 0,
+			apiactor.WithGenerateTraceId(),
+			apiactor.WithRetryPolicy(&apiactor.BackoffRetryPolicy{MaxAttempts: 2, RetryableStatusCodes: apiactor.DefaultRetryableStatusCodes}),
+			apiactor.WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				traceIds = append(traceIds, r.Header.Get(httpmw.XRequestId))
+				return http.DefaultTransport.RoundTrip(r)
+			})),
+		)
+
+		_, err := apiActor.Call(apiactor.NewRequest("GET", flakyServer.URL))
+		require.NoError(t, err)
+		require.Len(t, traceIds, 2)
+		require.NotEmpty(t, traceIds[0])
+		require.Equal(t, traceIds[0], traceIds[1])
+	})
+
+	t.Run("WithCtxHeader로 지정한 context의 값도 헤더로 전달되며, CallApi에도 적용된다.", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), mw.CtxKeyTraceID, "trace-2")
+		ctx = context.WithValue(ctx, ctxKeyTenant{}, "careerhub")
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		require.NoError(t, err)
+
+		_, err = apiactor.CallApi(httpReq, apiactor.WithCtxHeader(ctxKeyTenant{}, "X-Tenant"))
+		require.NoError(t, err)
+
+		header := <-headerChan
+		require.Equal(t, "trace-2", header.Get(httpmw.XRequestId))
+		require.Equal(t, "careerhub", header.Get("X-Tenant"))
+		require.Empty(t, httpReq.Header.Get(httpmw.XRequestId))
+	})
+}
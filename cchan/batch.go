@@ -0,0 +1,159 @@
+package cchan
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Batch는 inputChan의 데이터를 모아 maxSize개가 되거나, 모으기 시작한 후 maxWait이 지나면 모은 데이터를 전달한다.
+// inputChan이 닫히면 남은 데이터를 전달한 후 반환된 채널을 닫으며, context가 종료되면 남은 데이터는 전달하지 않고 닫는다.
+func Batch[T any](ctx context.Context, inputChan <-chan T, maxSize int, maxWait time.Duration) <-chan []T {
+	batchChan := make(chan []T)
+	go func() {
+		defer close(batchChan)
+
+		var batch []T
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+
+			ok := Send(ctx, batchChan, batch)
+			batch = nil // 전달한 slice를 재사용하지 않는다.
+			return ok
+		}
+
+		for {
+			if ctx.Err() != nil { // context의 종료 트리거를 우선순위로 둔다.
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-inputChan:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, data)
+				if len(batch) == 1 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) >= maxSize && !flush() {
+					return
+				}
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return batchChan
+}
+
+// Window는 [Start, End) 동안 전달된 데이터들이다.
+type Window[T any] struct {
+	Start time.Time
+	End   time.Time
+	Items []T
+}
+
+type timestamped[T any] struct {
+	at   time.Time
+	data T
+}
+
+// TumblingWindow는 inputChan의 데이터를 호출한 시각부터 size 간격으로 겹치지 않게 나눈 window로 전달한다.
+// 데이터가 없는 window는 전달하지 않는다.
+// inputChan이 닫히면 진행중인 window를 닫힌 시각을 End로 하여 전달한 후 반환된 채널을 닫으며, context가 종료되면 전달하지 않고 닫는다.
+// size가 0 이하이면 window가 끝나지 않으므로 panic이 발생한다.
+func TumblingWindow[T any](ctx context.Context, inputChan <-chan T, size time.Duration) <-chan Window[T] {
+	if size <= 0 {
+		panic(fmt.Sprintf("cchan: TumblingWindow requires a positive size, got %s", size))
+	}
+	return SlidingWindow(ctx, inputChan, size, size)
+}
+
+// SlidingWindow는 inputChan의 데이터를 size 길이의 window로 전달하며, window는 slide마다 시작된다.
+// slide가 size보다 작으면 하나의 데이터가 여러 window에 포함되고, 크면 window 사이의 데이터는 전달되지 않는다.
+// 데이터가 없는 window는 전달하지 않는다.
+// inputChan이 닫히면 진행중인 window를 닫힌 시각을 End로 하여 전달한 후 반환된 채널을 닫으며, context가 종료되면 전달하지 않고 닫는다.
+// size나 slide가 0 이하이면 같은 window가 반복되므로 panic이 발생한다.
+func SlidingWindow[T any](ctx context.Context, inputChan <-chan T, size, slide time.Duration) <-chan Window[T] {
+	if size <= 0 || slide <= 0 {
+		panic(fmt.Sprintf("cchan: SlidingWindow requires a positive size and slide, got size=%s, slide=%s", size, slide))
+	}
+
+	windowChan := make(chan Window[T])
+	go func() {
+		defer close(windowChan)
+
+		var buffered []timestamped[T]
+		end := time.Now().Add(size)
+		timer := time.NewTimer(size)
+		defer timer.Stop()
+
+		for {
+			if ctx.Err() != nil { // context의 종료 트리거를 우선순위로 둔다.
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-inputChan:
+				if !ok {
+					if window := windowOf(buffered, end.Add(-size), time.Now()); len(window.Items) > 0 {
+						Send(ctx, windowChan, window)
+					}
+					return
+				}
+				buffered = append(buffered, timestamped[T]{at: time.Now(), data: data})
+			case <-timer.C:
+				if window := windowOf(buffered, end.Add(-size), end); len(window.Items) > 0 {
+					if !Send(ctx, windowChan, window) {
+						return
+					}
+				}
+
+				end = end.Add(slide)
+				buffered = dropBefore(buffered, end.Add(-size))
+				timer.Reset(time.Until(end))
+			}
+		}
+	}()
+
+	return windowChan
+}
+
+func windowOf[T any](buffered []timestamped[T], start, end time.Time) Window[T] {
+	window := Window[T]{Start: start, End: end}
+	for _, b := range buffered {
+		if !b.at.Before(start) && b.at.Before(end) {
+			window.Items = append(window.Items, b.data)
+		}
+	}
+	return window
+}
+
+// dropBefore는 이후의 window에 포함되지 않을 데이터들을 제거한다.
+func dropBefore[T any](buffered []timestamped[T], start time.Time) []timestamped[T] {
+	i := 0
+	for i < len(buffered) && buffered[i].at.Before(start) {
+		i++
+	}
+	return buffered[i:]
+}
@@ -0,0 +1,128 @@
+package cchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/cchan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run("maxSize개가 모이면 전달한다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		batches := cchan.Batch(context.Background(), ch, 3, time.Hour)
+
+		sendAll(ch, 1, 2, 3, 4, 5, 6, 7)
+		require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}}, assertLength(t, batches, 2))
+
+		close(ch)
+		require.Equal(t, [][]int{{7}}, cchan.WaitClosed(batches))
+	})
+
+	t.Run("모으기 시작한 후 maxWait이 지나면 maxSize보다 적어도 전달한다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		batches := cchan.Batch(context.Background(), ch, 100, 20*time.Millisecond)
+
+		sendAll(ch, 1, 2)
+		require.Equal(t, []int{1, 2}, <-batches)
+
+		ch <- 3
+		require.Equal(t, []int{3}, <-batches)
+		assertLength(t, batches, 0) // 데이터가 없으면 전달하지 않는다.
+
+		close(ch)
+		require.Empty(t, cchan.WaitClosed(batches))
+	})
+
+	t.Run("context가 종료되면 남은 데이터를 전달하지 않고 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 100)
+		batches := cchan.Batch(ctx, ch, 100, time.Hour)
+
+		sendAll(ch, 1, 2)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		require.Empty(t, cchan.WaitClosed(batches))
+	})
+}
+
+func TestTumblingWindow(t *testing.T) {
+	t.Run("겹치지 않는 window마다 데이터를 전달한다.", func(t *testing.T) {
+		size := 50 * time.Millisecond
+		ch := make(chan int, 100)
+		windows := cchan.TumblingWindow(context.Background(), ch, size)
+
+		sendAll(ch, 1, 2)
+		first := <-windows
+		require.Equal(t, []int{1, 2}, first.Items)
+		require.Equal(t, size, first.End.Sub(first.Start))
+
+		ch <- 3
+		second := <-windows
+		require.Equal(t, []int{3}, second.Items)
+		require.Equal(t, first.End, second.Start)
+
+		close(ch)
+		require.Empty(t, cchan.WaitClosed(windows))
+	})
+
+	t.Run("inputChan이 닫히면 진행중인 window를 전달한 후 닫힌다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		windows := cchan.TumblingWindow(context.Background(), ch, time.Hour)
+
+		sendAll(ch, 1, 2)
+		time.Sleep(10 * time.Millisecond)
+		close(ch)
+
+		result := cchan.WaitClosed(windows)
+		require.Len(t, result, 1)
+		require.Equal(t, []int{1, 2}, result[0].Items)
+		require.Less(t, result[0].End.Sub(result[0].Start), time.Hour)
+	})
+
+	t.Run("context가 종료되면 진행중인 window를 전달하지 않고 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 100)
+		windows := cchan.TumblingWindow(ctx, ch, time.Hour)
+
+		sendAll(ch, 1, 2)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		require.Empty(t, cchan.WaitClosed(windows))
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("slide마다 size 길이의 window를 전달하므로, 하나의 데이터가 여러 window에 포함된다.", func(t *testing.T) {
+		size, slide := 200*time.Millisecond, 100*time.Millisecond
+		ch := make(chan int, 100)
+		windows := cchan.SlidingWindow(context.Background(), ch, size, slide)
+
+		ch <- 1
+		time.Sleep(130 * time.Millisecond)
+		ch <- 2
+
+		first := <-windows
+		require.Equal(t, []int{1, 2}, first.Items)
+		require.Equal(t, size, first.End.Sub(first.Start))
+
+		second := <-windows
+		require.Equal(t, []int{2}, second.Items)
+		require.Equal(t, slide, second.Start.Sub(first.Start))
+
+		close(ch)
+		require.Empty(t, cchan.WaitClosed(windows))
+	})
+	t.Run("size나 slide가 0 이하이면 panic이 발생한다.", func(t *testing.T) {
+		ch := make(chan int)
+		defer close(ch)
+
+		require.Panics(t, func() { cchan.SlidingWindow(context.Background(), ch, time.Second, 0) })
+		require.Panics(t, func() { cchan.SlidingWindow(context.Background(), ch, 0, time.Second) })
+		require.Panics(t, func() { cchan.TumblingWindow(context.Background(), ch, -time.Second) })
+	})
+}
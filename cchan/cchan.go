@@ -97,6 +97,7 @@ func SafeClose[T any](ch chan T) {
 	}
 }
 
+// Deprecated: TooMuchError는 중지하거나 초기화할 수 없으므로, ErrorRateMonitor를 사용한다.
 func TooMuchError[ERROR error](periodErrCount uint, limitErrPeriod time.Duration, errChan <-chan ERROR, tooMuchErrFunc func(), closedFunc func()) {
 	go func() {
 		var errCount uint = 0
@@ -126,6 +127,7 @@ func TooMuchError[ERROR error](periodErrCount uint, limitErrPeriod time.Duration
 	}()
 }
 
+// Deprecated: Timeout은 processedChan을 닫지 않으면 중지할 수 없으므로, Watchdog을 사용한다.
 func Timeout[DATA any](initDuration, duration time.Duration, processedChan <-chan DATA, timeoutFunc func(), closedFunc func()) {
 	go func() {
 		waitDuration := initDuration
@@ -0,0 +1,254 @@
+package cchan
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+)
+
+// FanOutPolicy는 출력 채널이 데이터를 바로 받지 못할 때의 처리 방법이다.
+type FanOutPolicy int
+
+const (
+	// FanOutBlock은 출력 채널이 데이터를 받을 때까지 기다리므로, 가장 느린 출력 채널의 속도에 맞춰진다.
+	FanOutBlock FanOutPolicy = iota
+	// FanOutDrop은 출력 채널의 buffer가 가득 차 있으면 그 채널에는 데이터를 전달하지 않는다.
+	FanOutDrop
+	// FanOutBuffer는 출력 채널마다 크기 제한이 없는 buffer에 데이터를 저장하므로, 느린 출력 채널이 다른 채널을 막지 않는다.
+	FanOutBuffer
+)
+
+// fanOutput은 하나의 출력 채널이며, 닫는 것은 데이터를 전달하는 goroutine만 할 수 있다.
+type fanOutput[T any] struct {
+	in     chan T        // 데이터를 전달하는 채널. FanOutBuffer이면 forwarder가 out으로 전달한다.
+	out    <-chan T      // 사용자에게 반환되는 채널
+	closed chan struct{} // 구독이 해지되면 닫힌다.
+}
+
+func newFanOutput[T any](ctx context.Context, bufferSize int, policy FanOutPolicy) *fanOutput[T] {
+	o := &fanOutput[T]{closed: make(chan struct{})}
+	if policy == FanOutBuffer {
+		o.in = make(chan T)
+		o.out = forwardUnbounded(ctx, o.in, o.closed)
+	} else {
+		o.in = make(chan T, bufferSize)
+		o.out = o.in
+	}
+	return o
+}
+
+// send는 policy에 따라 data를 전달하며, context가 종료되면 false를 반환한다.
+func (o *fanOutput[T]) send(ctx context.Context, policy FanOutPolicy, data T) bool {
+	if policy == FanOutDrop {
+		select {
+		case o.in <- data:
+		default:
+		}
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done(): // context의 종료 트리거를 우선순위로 둔다.
+		return false
+	default:
+		select {
+		case o.in <- data:
+		case <-o.closed:
+		case <-ctx.Done():
+			return false
+		}
+		return true
+	}
+}
+
+// forwardUnbounded는 in의 데이터를 크기 제한이 없는 queue에 저장하며 반환하는 채널로 전달한다.
+// in이 닫히면 queue의 데이터를 모두 전달한 후 반환하는 채널을 닫는다.
+func forwardUnbounded[T any](ctx context.Context, in <-chan T, closed <-chan struct{}) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		var queue []T
+		for in != nil || len(queue) > 0 {
+			var sendChan chan<- T
+			var next T
+			if len(queue) > 0 {
+				sendChan, next = out, queue[0]
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-closed:
+				return
+			case data, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, data)
+			case sendChan <- next:
+				queue = queue[1:]
+			}
+		}
+	}()
+
+	return out
+}
+
+func fanOutputs[T any](ctx context.Context, n, bufferSize int, policy FanOutPolicy) ([]*fanOutput[T], []<-chan T) {
+	outputs := make([]*fanOutput[T], n)
+	outChans := make([]<-chan T, n)
+	for i := range outputs {
+		outputs[i] = newFanOutput[T](ctx, bufferSize, policy)
+		outChans[i] = outputs[i].out
+	}
+	return outputs, outChans
+}
+
+// Tee는 inputChan의 모든 데이터를 n개의 출력 채널에 각각 전달한다. bufferSize는 FanOutBlock, FanOutDrop에서 출력 채널의 buffer 크기이다.
+// inputChan이 닫히거나 context가 종료되면 출력 채널들이 닫힌다. n이 0 이하이면 전달할 채널이 없으므로 panic이 발생한다.
+func Tee[T any](ctx context.Context, inputChan <-chan T, n int, bufferSize int, policy FanOutPolicy) []<-chan T {
+	if n <= 0 {
+		panic(fmt.Sprintf("cchan: Tee requires at least one output, got n=%d", n))
+	}
+
+	outputs, outChans := fanOutputs[T](ctx, n, bufferSize, policy)
+
+	go func() {
+		defer closeOutputs(outputs)
+
+		for {
+			data, ok := Receive(ctx, inputChan)
+			if !ok {
+				return
+			}
+			for _, o := range outputs {
+				if !o.send(ctx, policy, *data) {
+					return
+				}
+			}
+		}
+	}()
+
+	return outChans
+}
+
+// Partition은 inputChan의 데이터를 key의 hash에 따라 n개의 출력 채널 중 하나에 전달하므로, 같은 key의 데이터는 항상 같은 채널로 전달된다.
+// inputChan이 닫히거나 context가 종료되면 출력 채널들이 닫힌다. n이 0 이하이면 전달할 채널이 없으므로 panic이 발생한다.
+func Partition[T any](ctx context.Context, inputChan <-chan T, n int, key func(T) string, bufferSize int, policy FanOutPolicy) []<-chan T {
+	if n <= 0 {
+		panic(fmt.Sprintf("cchan: Partition requires at least one output, got n=%d", n))
+	}
+
+	outputs, outChans := fanOutputs[T](ctx, n, bufferSize, policy)
+	seed := maphash.MakeSeed()
+
+	go func() {
+		defer closeOutputs(outputs)
+
+		for {
+			data, ok := Receive(ctx, inputChan)
+			if !ok {
+				return
+			}
+
+			o := outputs[maphash.String(seed, key(*data))%uint64(n)]
+			if !o.send(ctx, policy, *data) {
+				return
+			}
+		}
+	}()
+
+	return outChans
+}
+
+func closeOutputs[T any](outputs []*fanOutput[T]) {
+	for _, o := range outputs {
+		close(o.in)
+	}
+}
+
+// Broadcaster는 inputChan의 데이터를 구독중인 모든 채널에 전달한다. 구독하기 전의 데이터는 전달되지 않는다.
+type Broadcaster[T any] struct {
+	ctx        context.Context
+	bufferSize int
+	policy     FanOutPolicy
+
+	subscribeChan   chan *fanOutput[T]
+	unsubscribeChan chan *fanOutput[T]
+	exited          chan struct{}
+}
+
+// Broadcast는 inputChan이 닫히거나 context가 종료되면 모든 구독 채널을 닫는다.
+func Broadcast[T any](ctx context.Context, inputChan <-chan T, bufferSize int, policy FanOutPolicy) *Broadcaster[T] {
+	b := &Broadcaster[T]{
+		ctx:             ctx,
+		bufferSize:      bufferSize,
+		policy:          policy,
+		subscribeChan:   make(chan *fanOutput[T]),
+		unsubscribeChan: make(chan *fanOutput[T]),
+		exited:          make(chan struct{}),
+	}
+	go b.run(inputChan)
+
+	return b
+}
+
+func (b *Broadcaster[T]) run(inputChan <-chan T) {
+	subscribers := make(map[*fanOutput[T]]struct{})
+	defer close(b.exited)
+	defer func() {
+		for o := range subscribers {
+			close(o.in)
+		}
+	}()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case o := <-b.subscribeChan:
+			subscribers[o] = struct{}{}
+		case o := <-b.unsubscribeChan:
+			delete(subscribers, o)
+			close(o.in)
+		case data, ok := <-inputChan:
+			if !ok {
+				return
+			}
+			for o := range subscribers {
+				if !o.send(b.ctx, b.policy, data) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Subscribe는 이후의 데이터를 받을 채널과 구독을 해지하는 함수를 반환한다. 구독을 해지하면 더 이상 전달되지 않으며 곧 채널이 닫힌다.
+// Broadcaster가 이미 종료되었다면 닫힌 채널을 반환한다.
+func (b *Broadcaster[T]) Subscribe() (<-chan T, func()) {
+	o := newFanOutput[T](b.ctx, b.bufferSize, b.policy)
+
+	select {
+	case b.subscribeChan <- o:
+	case <-b.exited:
+		close(o.in)
+		return o.out, func() {}
+	}
+
+	once := sync.Once{}
+	return o.out, func() {
+		once.Do(func() {
+			close(o.closed) // 이 채널로 전달하기 위해 기다리고 있는 Broadcaster를 깨운다.
+			go func() {     // Broadcaster가 다른 구독 채널로 전달하기 위해 기다리고 있을 수 있으므로 기다리지 않는다.
+				select {
+				case b.unsubscribeChan <- o:
+				case <-b.exited:
+				}
+			}()
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package cchan_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/cchan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	t.Run("모든 데이터를 각 출력 채널에 전달한다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		outs := cchan.Tee(context.Background(), ch, 3, 100, cchan.FanOutBlock)
+
+		sendAll(ch, 1, 2, 3)
+		close(ch)
+
+		require.Len(t, outs, 3)
+		for _, out := range outs {
+			require.Equal(t, []int{1, 2, 3}, cchan.WaitClosed(out))
+		}
+	})
+
+	t.Run("FanOutBlock은 출력 채널이 데이터를 받을 때까지 다음 데이터를 전달하지 않는다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		outs := cchan.Tee(context.Background(), ch, 2, 0, cchan.FanOutBlock)
+
+		sendAll(ch, 1, 2)
+		time.Sleep(10 * time.Millisecond)
+
+		require.Equal(t, 1, <-outs[0])
+		assertLength(t, outs[0], 0) // outs[1]이 받을 때까지 기다린다.
+		require.Equal(t, 1, <-outs[1])
+		require.Equal(t, 2, <-outs[0])
+		require.Equal(t, 2, <-outs[1])
+
+		close(ch)
+		require.Empty(t, cchan.WaitClosed(outs[0]))
+	})
+
+	t.Run("FanOutDrop은 가득 찬 출력 채널에 데이터를 전달하지 않는다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		outs := cchan.Tee(context.Background(), ch, 2, 1, cchan.FanOutDrop)
+
+		ch <- 1
+		require.Equal(t, 1, <-outs[0])
+		sendAll(ch, 2, 3) // outs[0]에는 2만, 이미 가득 찬 outs[1]에는 아무것도 전달되지 않는다.
+		close(ch)
+		time.Sleep(10 * time.Millisecond)
+
+		require.Equal(t, []int{2}, cchan.WaitClosed(outs[0]))
+		require.Equal(t, []int{1}, cchan.WaitClosed(outs[1]))
+	})
+
+	t.Run("FanOutBuffer는 느린 출력 채널이 다른 출력 채널을 막지 않는다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		outs := cchan.Tee(context.Background(), ch, 2, 0, cchan.FanOutBuffer)
+
+		sendAll(ch, 1, 2, 3)
+		close(ch)
+
+		require.Equal(t, []int{1, 2, 3}, cchan.WaitClosed(outs[0]))
+		require.Equal(t, []int{1, 2, 3}, cchan.WaitClosed(outs[1]))
+	})
+
+	t.Run("context가 종료되면 출력 채널들이 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 100)
+		outs := cchan.Tee(ctx, ch, 2, 0, cchan.FanOutBuffer)
+
+		cancel()
+		for _, out := range outs {
+			require.Empty(t, cchan.WaitClosed(out))
+		}
+	})
+	t.Run("출력 채널의 개수가 0 이하이면 호출할 때 panic이 발생한다.", func(t *testing.T) {
+		ch := make(chan int)
+		require.Panics(t, func() { cchan.Tee(context.Background(), ch, 0, 0, cchan.FanOutBlock) })
+		require.Panics(t, func() { cchan.Tee(context.Background(), ch, -1, 0, cchan.FanOutBlock) })
+	})
+}
+
+func TestPartition(t *testing.T) {
+	t.Run("같은 key의 데이터는 같은 출력 채널로 순서대로 전달된다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		outs := cchan.Partition(context.Background(), ch, 3, func(i int) string { return strconv.Itoa(i % 5) }, 100, cchan.FanOutBlock)
+
+		for i := 0; i < 50; i++ {
+			ch <- i
+		}
+		close(ch)
+
+		partitionOf := make(map[int]int)
+		total := 0
+		for i, out := range outs {
+			items := cchan.WaitClosed(out)
+			total += len(items)
+
+			for j, item := range items {
+				if partition, ok := partitionOf[item%5]; ok {
+					require.Equal(t, i, partition)
+				}
+				partitionOf[item%5] = i
+				if j > 0 {
+					require.Less(t, items[j-1], item)
+				}
+			}
+		}
+		require.Equal(t, 50, total)
+	})
+
+	t.Run("출력 채널의 개수가 0 이하이면 호출할 때 panic이 발생한다.", func(t *testing.T) {
+		ch := make(chan int)
+		require.Panics(t, func() { cchan.Partition(context.Background(), ch, 0, strconv.Itoa, 0, cchan.FanOutBlock) })
+		require.Panics(t, func() { cchan.Partition(context.Background(), ch, -1, strconv.Itoa, 0, cchan.FanOutBlock) })
+	})
+
+	t.Run("context가 종료되면 출력 채널들이 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 100)
+		outs := cchan.Partition(ctx, ch, 2, strconv.Itoa, 0, cchan.FanOutBlock)
+
+		cancel()
+		for _, out := range outs {
+			require.Empty(t, cchan.WaitClosed(out))
+		}
+	})
+}
+
+func TestBroadcast(t *testing.T) {
+	t.Run("구독한 이후의 데이터를 모든 구독 채널에 전달한다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		b := cchan.Broadcast(context.Background(), ch, 100, cchan.FanOutBlock)
+
+		sub1, _ := b.Subscribe()
+		ch <- 1
+		require.Equal(t, []int{1}, assertLength(t, sub1, 1))
+
+		sub2, _ := b.Subscribe()
+		sendAll(ch, 2, 3)
+		close(ch)
+
+		require.Equal(t, []int{2, 3}, cchan.WaitClosed(sub1))
+		require.Equal(t, []int{2, 3}, cchan.WaitClosed(sub2))
+	})
+
+	t.Run("구독을 해지하면 구독 채널이 닫히고 다른 구독 채널에는 계속 전달된다.", func(t *testing.T) {
+		ch := make(chan int, 100)
+		b := cchan.Broadcast(context.Background(), ch, 0, cchan.FanOutBlock)
+
+		sub1, unsubscribe := b.Subscribe()
+		sub2, _ := b.Subscribe()
+
+		ch <- 1
+		time.Sleep(10 * time.Millisecond)
+		unsubscribe() // sub1로 전달하기 위해 기다리던 Broadcaster를 깨운다.
+		unsubscribe()
+		require.Equal(t, 1, <-sub2)
+		cchan.WaitClosed(sub1)
+
+		ch <- 2
+		require.Equal(t, 2, <-sub2)
+
+		close(ch)
+		require.Empty(t, cchan.WaitClosed(sub2))
+	})
+
+	t.Run("종료된 Broadcaster를 구독하면 닫힌 채널을 반환한다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 100)
+		b := cchan.Broadcast(ctx, ch, 0, cchan.FanOutBuffer)
+
+		sub1, _ := b.Subscribe()
+		cancel()
+		require.Empty(t, cchan.WaitClosed(sub1))
+
+		sub2, unsubscribe := b.Subscribe()
+		require.Empty(t, cchan.WaitClosed(sub2))
+		unsubscribe()
+	})
+}
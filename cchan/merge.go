@@ -0,0 +1,133 @@
+package cchan
+
+import (
+	"container/heap"
+	"context"
+	"reflect"
+)
+
+// MergeSorted는 각각 정렬된 채널들을 cmp의 순서대로 정렬된 하나의 채널로 합친다.
+// 다음 데이터를 결정하려면 닫히지 않은 모든 채널의 데이터가 필요하므로, 데이터가 전달되지 않는 채널이 있으면 기다린다.
+// 모든 채널이 닫히거나 context가 종료되면 반환된 채널이 닫힌다.
+func MergeSorted[T any](ctx context.Context, cmp func(a, b T) int, chans ...<-chan T) <-chan T {
+	mergedChan := make(chan T)
+	go func() {
+		defer close(mergedChan)
+
+		heads := &mergeHeap[T]{cmp: cmp}
+		for _, ch := range chans {
+			if !heads.receive(ctx, ch) && ctx.Err() != nil {
+				return
+			}
+		}
+
+		for heads.Len() > 0 {
+			head := heap.Pop(heads).(mergeHead[T])
+			if !Send(ctx, mergedChan, head.data) {
+				return
+			}
+			if !heads.receive(ctx, head.ch) && ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	return mergedChan
+}
+
+type mergeHead[T any] struct {
+	data T
+	ch   <-chan T
+	seq  int // 같은 값이면 먼저 받은 데이터를 먼저 전달한다.
+}
+
+type mergeHeap[T any] struct {
+	cmp   func(a, b T) int
+	heads []mergeHead[T]
+	seq   int
+}
+
+// receive는 ch의 다음 데이터를 받아 heap에 추가한다. ch가 닫혔거나 context가 종료되면 false를 반환한다.
+func (h *mergeHeap[T]) receive(ctx context.Context, ch <-chan T) bool {
+	data, ok := Receive(ctx, ch)
+	if !ok {
+		return false
+	}
+
+	h.seq++
+	heap.Push(h, mergeHead[T]{data: *data, ch: ch, seq: h.seq})
+	return true
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.heads) }
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if c := h.cmp(h.heads[i].data, h.heads[j].data); c != 0 {
+		return c < 0
+	}
+	return h.heads[i].seq < h.heads[j].seq
+}
+func (h *mergeHeap[T]) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *mergeHeap[T]) Push(x any)    { h.heads = append(h.heads, x.(mergeHead[T])) }
+func (h *mergeHeap[T]) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
+
+// MergePriority는 채널들을 하나의 채널로 합치며, 앞에 있는 채널일수록 우선순위가 높다.
+// 우선순위가 높은 채널에 데이터가 있으면 항상 먼저 전달하므로, 우선순위가 낮은 채널은 높은 채널들이 모두 비어있을 때만 전달된다.
+// 모든 채널이 닫히거나 context가 종료되면 반환된 채널이 닫힌다.
+func MergePriority[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	mergedChan := make(chan T)
+	go func() {
+		defer close(mergedChan)
+
+		remained := make([]<-chan T, len(chans))
+		copy(remained, chans)
+
+		for len(remained) > 0 {
+			data, i, ok := receivePriority(ctx, remained)
+			if ctx.Err() != nil {
+				return
+			}
+			if !ok { // i번째 채널이 닫혔다.
+				remained = append(remained[:i], remained[i+1:]...)
+				continue
+			}
+
+			if !Send(ctx, mergedChan, data) {
+				return
+			}
+		}
+	}()
+
+	return mergedChan
+}
+
+// receivePriority는 데이터가 있는 채널 중 가장 앞에 있는 채널에서 데이터를 받는다. 모든 채널이 비어있으면 데이터가 들어올 때까지 기다린다.
+func receivePriority[T any](ctx context.Context, chans []<-chan T) (T, int, bool) {
+	for i, ch := range chans {
+		select {
+		case data, ok := <-ch:
+			return data, i, ok
+		default:
+		}
+	}
+
+	cases := make([]reflect.SelectCase, 0, len(chans)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	for _, ch := range chans {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+	}
+
+	chosen, value, ok := reflect.Select(cases)
+	if chosen == 0 {
+		return *new(T), 0, false
+	}
+
+	var data T
+	if ok {
+		data, _ = value.Interface().(T) // T가 interface이고 nil을 받았다면 zero value이다.
+	}
+	return data, chosen - 1, ok
+}
@@ -0,0 +1,115 @@
+package cchan_test
+
+import (
+	"cmp"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/cchan"
+	"github.com/stretchr/testify/require"
+)
+
+func sendAll[T any](ch chan<- T, items ...T) {
+	for _, item := range items {
+		ch <- item
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	t.Run("정렬된 채널들을 정렬된 하나의 채널로 병합한다.", func(t *testing.T) {
+		ch1 := make(chan int, 100)
+		ch2 := make(chan int, 100)
+		ch3 := make(chan int, 100)
+
+		merged := cchan.MergeSorted(context.Background(), cmp.Compare[int], ch1, ch2, ch3)
+
+		sendAll(ch1, 1, 4, 7, 10)
+		sendAll(ch2, 2, 5, 8)
+		sendAll(ch3, 3, 6, 9)
+		close(ch1)
+		close(ch2)
+		close(ch3)
+
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, cchan.WaitClosed(merged))
+	})
+
+	t.Run("닫히지 않은 채널에 데이터가 없으면 다음 데이터를 전달하지 않고 기다린다.", func(t *testing.T) {
+		ch1 := make(chan int, 100)
+		ch2 := make(chan int, 100)
+
+		merged := cchan.MergeSorted(context.Background(), cmp.Compare[int], ch1, ch2)
+
+		sendAll(ch1, 1, 3)
+		assertLength(t, merged, 0)
+
+		ch2 <- 2
+		require.Equal(t, []int{1, 2}, assertLength(t, merged, 2))
+
+		close(ch2)
+		require.Equal(t, []int{3}, assertLength(t, merged, 1))
+		close(ch1)
+		require.Empty(t, cchan.WaitClosed(merged))
+	})
+
+	t.Run("context가 종료되면 병합된 채널이 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch1 := make(chan int, 100)
+
+		merged := cchan.MergeSorted(ctx, cmp.Compare[int], ch1)
+		cancel()
+
+		require.Empty(t, cchan.WaitClosed(merged))
+	})
+}
+
+func TestMergePriority(t *testing.T) {
+	t.Run("우선순위가 높은 채널의 데이터를 먼저 전달한다.", func(t *testing.T) {
+		high := make(chan string, 100)
+		low := make(chan string, 100)
+
+		sendAll(low, "low1", "low2")
+		sendAll(high, "high1", "high2")
+
+		merged := cchan.MergePriority(context.Background(), high, low)
+		time.Sleep(10 * time.Millisecond)
+
+		require.Equal(t, "high1", <-merged)
+		high <- "high3"
+		require.Equal(t, "high2", <-merged)
+		require.Equal(t, "high3", <-merged)
+		require.Equal(t, "low1", <-merged)
+
+		close(high)
+		close(low)
+		require.Equal(t, []string{"low2"}, cchan.WaitClosed(merged))
+	})
+
+	t.Run("모든 채널이 비어있으면 데이터가 들어오는 채널의 데이터를 전달한다.", func(t *testing.T) {
+		high := make(chan string)
+		low := make(chan string)
+
+		merged := cchan.MergePriority(context.Background(), high, low)
+
+		go func() { low <- "low" }()
+		require.Equal(t, "low", <-merged)
+
+		go func() { high <- "high" }()
+		require.Equal(t, "high", <-merged)
+
+		close(high)
+		close(low)
+		require.Empty(t, cchan.WaitClosed(merged))
+	})
+
+	t.Run("context가 종료되면 병합된 채널이 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		high := make(chan string, 100)
+		low := make(chan string, 100)
+
+		merged := cchan.MergePriority(ctx, high, low)
+		cancel()
+
+		require.Empty(t, cchan.WaitClosed(merged))
+	})
+}
@@ -0,0 +1,182 @@
+package cchan
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ErrorRateConfig는 ErrorRateMonitor의 window와 임계치이다. window의 크기와 기간을 함께 지정하면 두 조건을 모두 만족하는 결과들만 남는다.
+// 결과가 제한없이 쌓이지 않도록, 둘 다 0이면 WindowSize는 DefaultErrorRateWindowSize이다.
+type ErrorRateConfig struct {
+	WindowSize     int           // 최근 WindowSize개의 결과만 고려한다. 0이면 개수를 제한하지 않는다.
+	WindowDuration time.Duration // 최근 WindowDuration 동안의 결과만 고려한다. 0이면 기간을 제한하지 않는다.
+
+	MaxErrors     int     // window 안의 에러가 MaxErrors개 이상이면 임계치를 넘은 것이다. 0이면 무시한다.
+	MaxErrorRatio float64 // window 안의 결과 중 에러의 비율이 MaxErrorRatio 이상이면 임계치를 넘은 것이다. 0이면 무시한다.
+	MinSamples    int     // window 안의 결과가 MinSamples개 이상일 때만 에러의 비율을 계산한다.
+}
+
+const DefaultErrorRateWindowSize = 100
+
+// ErrorRateStats는 현재 window 안의 결과들의 통계이다.
+type ErrorRateStats struct {
+	Errors    int
+	Successes int
+}
+
+func (s ErrorRateStats) Ratio() float64 {
+	if total := s.Errors + s.Successes; total > 0 {
+		return float64(s.Errors) / float64(total)
+	}
+	return 0
+}
+
+type outcome[ERROR error] struct {
+	at    time.Time
+	err   ERROR
+	isErr bool
+}
+
+// ErrorRateMonitor는 성공과 에러를 기록하여, window 안의 에러가 임계치를 넘으면 window 안의 에러들과 함께 onTooMuchError를 호출한다.
+// onTooMuchError를 호출한 후에는 window를 비우므로, 이후에 다시 임계치를 넘어야 다시 호출된다.
+// ctx가 종료되거나 Stop이 호출되면 더 이상 기록하지 않는다.
+type ErrorRateMonitor[ERROR error] struct {
+	config         ErrorRateConfig
+	onTooMuchError func(errs []ERROR)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	outcomes []outcome[ERROR]
+}
+
+func NewErrorRateMonitor[ERROR error](ctx context.Context, config ErrorRateConfig, onTooMuchError func(errs []ERROR)) *ErrorRateMonitor[ERROR] {
+	if config.WindowSize <= 0 && config.WindowDuration <= 0 {
+		config.WindowSize = DefaultErrorRateWindowSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &ErrorRateMonitor[ERROR]{
+		config:         config,
+		onTooMuchError: onTooMuchError,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+func (m *ErrorRateMonitor[ERROR]) Success() {
+	m.record(outcome[ERROR]{at: time.Now()})
+}
+
+func (m *ErrorRateMonitor[ERROR]) Error(err ERROR) {
+	m.record(outcome[ERROR]{at: time.Now(), err: err, isErr: true})
+}
+
+func (m *ErrorRateMonitor[ERROR]) record(o outcome[ERROR]) {
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.outcomes = append(m.outcomes, o)
+	m.prune(o.at)
+
+	errs, tooMuch := m.check()
+	if tooMuch {
+		m.outcomes = nil
+	}
+	m.mu.Unlock()
+
+	if tooMuch { // 콜백에서 monitor를 호출할 수 있도록 lock을 해제한 후 호출한다.
+		m.onTooMuchError(errs)
+	}
+}
+
+// prune은 window를 벗어난 결과들을 제거한다.
+func (m *ErrorRateMonitor[ERROR]) prune(now time.Time) {
+	start := 0
+	if m.config.WindowSize > 0 && len(m.outcomes) > m.config.WindowSize {
+		start = len(m.outcomes) - m.config.WindowSize
+	}
+	if m.config.WindowDuration > 0 {
+		for start < len(m.outcomes) && now.Sub(m.outcomes[start].at) > m.config.WindowDuration {
+			start++
+		}
+	}
+	m.outcomes = m.outcomes[start:]
+}
+
+func (m *ErrorRateMonitor[ERROR]) check() ([]ERROR, bool) {
+	var errs []ERROR
+	for _, o := range m.outcomes {
+		if o.isErr {
+			errs = append(errs, o.err)
+		}
+	}
+
+	if m.config.MaxErrors > 0 && len(errs) >= m.config.MaxErrors {
+		return errs, true
+	}
+
+	stats := ErrorRateStats{Errors: len(errs), Successes: len(m.outcomes) - len(errs)}
+	if m.config.MaxErrorRatio > 0 && len(m.outcomes) >= max(m.config.MinSamples, 1) && stats.Ratio() >= m.config.MaxErrorRatio {
+		return errs, true
+	}
+
+	return errs, false
+}
+
+// Stats는 현재 window 안의 결과들의 통계를 반환한다.
+func (m *ErrorRateMonitor[ERROR]) Stats() ErrorRateStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(time.Now())
+
+	stats := ErrorRateStats{}
+	for _, o := range m.outcomes {
+		if o.isErr {
+			stats.Errors++
+		} else {
+			stats.Successes++
+		}
+	}
+	return stats
+}
+
+// Reset은 window 안의 결과들을 모두 제거한다.
+func (m *ErrorRateMonitor[ERROR]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.outcomes = nil
+}
+
+func (m *ErrorRateMonitor[ERROR]) Stop() {
+	m.cancel()
+}
+
+// Done은 ctx가 종료되거나 Stop이 호출되면 닫힌다.
+func (m *ErrorRateMonitor[ERROR]) Done() <-chan struct{} {
+	return m.ctx.Done()
+}
+
+// Watch는 errChan으로 전달되는 에러들을 기록하며, errChan이 닫히거나 monitor가 종료되면 반환되는 채널이 닫힌다.
+func (m *ErrorRateMonitor[ERROR]) Watch(errChan <-chan ERROR) <-chan struct{} {
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+
+		for {
+			err, ok := Receive(m.ctx, errChan)
+			if !ok {
+				return
+			}
+			m.Error(*err)
+		}
+	}()
+
+	return watchDone
+}
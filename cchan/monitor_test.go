@@ -0,0 +1,116 @@
+package cchan_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/cchan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorRateMonitor(t *testing.T) {
+	newMonitor := func(ctx context.Context, config cchan.ErrorRateConfig) (*cchan.ErrorRateMonitor[error], <-chan []error) {
+		tooMuchErrChan := make(chan []error, 10)
+		monitor := cchan.NewErrorRateMonitor(ctx, config, func(errs []error) {
+			tooMuchErrChan <- errs
+		})
+		return monitor, tooMuchErrChan
+	}
+
+	t.Run("최근 WindowSize개의 결과 중 에러가 MaxErrors개 이상이면 에러들과 함께 콜백이 호출된다.", func(t *testing.T) {
+		monitor, tooMuchErrChan := newMonitor(context.Background(), cchan.ErrorRateConfig{WindowSize: 5, MaxErrors: 3})
+
+		monitor.Error(errors.New("error 1"))
+		for i := 0; i < 4; i++ {
+			monitor.Success()
+		}
+		monitor.Error(errors.New("error 2")) // error 1은 window를 벗어났다.
+		monitor.Error(errors.New("error 3"))
+		assertLength(t, tooMuchErrChan, 0)
+		require.Equal(t, cchan.ErrorRateStats{Errors: 2, Successes: 3}, monitor.Stats())
+
+		monitor.Error(errors.New("error 4"))
+		errs := assertLength(t, tooMuchErrChan, 1)[0]
+		require.Equal(t, []error{errors.New("error 2"), errors.New("error 3"), errors.New("error 4")}, errs)
+		require.Equal(t, cchan.ErrorRateStats{}, monitor.Stats()) // 콜백이 호출되면 window를 비운다.
+	})
+
+	t.Run("WindowDuration이 지난 결과는 고려하지 않는다.", func(t *testing.T) {
+		monitor, tooMuchErrChan := newMonitor(context.Background(), cchan.ErrorRateConfig{WindowDuration: 100 * time.Millisecond, MaxErrors: 2})
+
+		monitor.Error(errors.New("error 1"))
+		time.Sleep(150 * time.Millisecond)
+		monitor.Error(errors.New("error 2"))
+		assertLength(t, tooMuchErrChan, 0)
+
+		monitor.Error(errors.New("error 3"))
+		assertLength(t, tooMuchErrChan, 1)
+	})
+
+	t.Run("결과가 MinSamples개 이상이고 에러의 비율이 MaxErrorRatio 이상이면 콜백이 호출된다.", func(t *testing.T) {
+		monitor, tooMuchErrChan := newMonitor(context.Background(), cchan.ErrorRateConfig{WindowSize: 10, MaxErrorRatio: 0.5, MinSamples: 4})
+
+		monitor.Error(errors.New("error 1"))
+		monitor.Error(errors.New("error 2"))
+		monitor.Success()
+		assertLength(t, tooMuchErrChan, 0) // 비율은 넘었지만 결과가 MinSamples개보다 적다.
+		require.InDelta(t, 0.667, monitor.Stats().Ratio(), 0.001)
+
+		monitor.Success()
+		assertLength(t, tooMuchErrChan, 1)
+	})
+
+	t.Run("WindowSize와 WindowDuration이 모두 0이면 최근 DefaultErrorRateWindowSize개의 결과만 고려한다.", func(t *testing.T) {
+		monitor, _ := newMonitor(context.Background(), cchan.ErrorRateConfig{MaxErrors: 1000})
+
+		for i := 0; i < 10*cchan.DefaultErrorRateWindowSize; i++ {
+			monitor.Success()
+		}
+		require.Equal(t, cchan.ErrorRateStats{Successes: cchan.DefaultErrorRateWindowSize}, monitor.Stats())
+	})
+
+	t.Run("Reset하면 window를 비운다.", func(t *testing.T) {
+		monitor, tooMuchErrChan := newMonitor(context.Background(), cchan.ErrorRateConfig{MaxErrors: 2})
+
+		monitor.Error(errors.New("error 1"))
+		monitor.Reset()
+		monitor.Error(errors.New("error 2"))
+		assertLength(t, tooMuchErrChan, 0)
+	})
+
+	t.Run("Watch는 채널의 에러들을 기록하며, 채널이 닫히면 종료된다.", func(t *testing.T) {
+		monitor, tooMuchErrChan := newMonitor(context.Background(), cchan.ErrorRateConfig{MaxErrors: 3})
+		errChan := make(chan error, 10)
+
+		watchDone := monitor.Watch(errChan)
+		for i := 0; i < 3; i++ {
+			errChan <- fmt.Errorf("error %d", i)
+		}
+		close(errChan)
+
+		<-watchDone
+		assertLength(t, tooMuchErrChan, 1)
+	})
+
+	t.Run("Stop하거나 ctx가 종료되면 더 이상 기록하지 않고 Watch도 종료된다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		monitor, tooMuchErrChan := newMonitor(ctx, cchan.ErrorRateConfig{MaxErrors: 1})
+		errChan := make(chan error)
+
+		watchDone := monitor.Watch(errChan)
+		cancel()
+
+		<-monitor.Done()
+		<-watchDone
+		monitor.Error(errors.New("error"))
+		assertLength(t, tooMuchErrChan, 0)
+
+		monitor, tooMuchErrChan = newMonitor(context.Background(), cchan.ErrorRateConfig{MaxErrors: 1})
+		monitor.Stop()
+		monitor.Error(errors.New("error"))
+		assertLength(t, tooMuchErrChan, 0)
+	})
+}
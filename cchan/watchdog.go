@@ -0,0 +1,188 @@
+package cchan
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type WatchdogLevel int
+
+const (
+	WatchdogTimeout WatchdogLevel = iota
+	WatchdogWarn
+	WatchdogAbort // Abort 이벤트가 발생하면 Watchdog은 종료된다.
+)
+
+func (l WatchdogLevel) String() string {
+	switch l {
+	case WatchdogTimeout:
+		return "timeout"
+	case WatchdogWarn:
+		return "warn"
+	case WatchdogAbort:
+		return "abort"
+	default:
+		return fmt.Sprintf("WatchdogLevel(%d)", int(l))
+	}
+}
+
+type WatchdogEvent struct {
+	Level    WatchdogLevel
+	Timeouts int           // 연속으로 timeout된 횟수
+	Idle     time.Duration // 마지막 Kick 이후 경과한 시간
+}
+
+type WatchdogConfig struct {
+	InitTimeout time.Duration // 첫 Kick 전까지의 timeout이며, 0 이하이면 Timeout을 사용한다.
+	Timeout     time.Duration // 0 이하이면 timeout이 계속 발생하므로 NewWatchdog에서 panic이 발생한다.
+	// Repeat이 false이면 한번 timeout된 후 다시 Kick될 때까지 timeout되지 않는다.
+	// true이면 Kick되지 않는 동안 Timeout마다 반복하여 timeout된다.
+	Repeat bool
+	// 연속으로 WarnAfter번 이상 timeout되면 Warn, AbortAfter번 timeout되면 Abort 이벤트가 발생한다. 0이면 해당 이벤트가 발생하지 않는다.
+	// Repeat이 false이면 timeout된 후의 Kick은 횟수를 초기화하지 않으므로, timeout되기 전에 Kick되어야 횟수가 초기화된다.
+	WarnAfter  int
+	AbortAfter int
+}
+
+// Watchdog은 Timeout동안 Kick되지 않으면 이벤트를 발생시킨다.
+// 이벤트는 onEvent가 nil이 아니면 onEvent로, nil이면 Events 채널로 전달되며, Events 채널이 가득 차 있으면 이벤트는 버려진다.
+type Watchdog struct {
+	config  WatchdogConfig
+	onEvent func(WatchdogEvent)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	kicked chan time.Time
+	events chan WatchdogEvent
+}
+
+const watchdogEventBufferSize = 16
+
+func NewWatchdog(ctx context.Context, config WatchdogConfig, onEvent func(WatchdogEvent)) *Watchdog {
+	if config.Timeout <= 0 {
+		panic(fmt.Sprintf("cchan: NewWatchdog requires a positive Timeout, got %s", config.Timeout))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watchdog{
+		config:  config,
+		onEvent: onEvent,
+		ctx:     ctx,
+		cancel:  cancel,
+		kicked:  make(chan time.Time, 1),
+		events:  make(chan WatchdogEvent, watchdogEventBufferSize),
+	}
+	go w.run()
+
+	return w
+}
+
+// Kick은 작업이 진행되었음을 알리며, timeout과 연속으로 timeout된 횟수를 초기화한다.
+// Repeat이 false이고 이미 timeout되었다면, 다시 감시를 시작하지만 횟수는 초기화하지 않는다.
+func (w *Watchdog) Kick() {
+	select {
+	case w.kicked <- time.Now():
+	default: // 아직 처리되지 않은 Kick이 있다.
+	}
+}
+
+func (w *Watchdog) Stop() {
+	w.cancel()
+}
+
+// Done은 ctx가 종료되거나, Stop이 호출되거나, Abort 이벤트가 발생하면 닫힌다.
+func (w *Watchdog) Done() <-chan struct{} {
+	return w.ctx.Done()
+}
+
+// Events는 onEvent가 nil일 때 이벤트가 전달되는 채널이며, Watchdog이 종료되면 닫힌다.
+func (w *Watchdog) Events() <-chan WatchdogEvent {
+	return w.events
+}
+
+func (w *Watchdog) run() {
+	defer close(w.events)
+	defer w.cancel()
+
+	timeout := w.config.InitTimeout
+	if timeout <= 0 {
+		timeout = w.config.Timeout
+	}
+
+	lastKicked := time.Now() // timer보다 먼저 설정해야 첫 이벤트의 Idle이 timeout보다 짧지 않다.
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	timeouts := 0
+	expired := false // Repeat이 false일 때, timeout된 후 아직 Kick되지 않았다.
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case lastKicked = <-w.kicked:
+			if !expired {
+				timeouts = 0
+			}
+			expired = false
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(w.config.Timeout)
+		case now := <-timer.C:
+			timeouts++
+			event := WatchdogEvent{Level: w.level(timeouts), Timeouts: timeouts, Idle: now.Sub(lastKicked)}
+			w.emit(event)
+
+			if event.Level == WatchdogAbort {
+				return
+			}
+			if w.config.Repeat {
+				timer.Reset(w.config.Timeout)
+			} else {
+				expired = true
+			}
+		}
+	}
+}
+
+func (w *Watchdog) level(timeouts int) WatchdogLevel {
+	switch {
+	case w.config.AbortAfter > 0 && timeouts >= w.config.AbortAfter:
+		return WatchdogAbort
+	case w.config.WarnAfter > 0 && timeouts >= w.config.WarnAfter:
+		return WatchdogWarn
+	default:
+		return WatchdogTimeout
+	}
+}
+
+func (w *Watchdog) emit(event WatchdogEvent) {
+	if w.onEvent != nil {
+		w.onEvent(event)
+		return
+	}
+
+	select {
+	case w.events <- event:
+	default:
+	}
+}
+
+// KickOnReceive는 ch로 데이터가 전달될 때마다 w를 Kick하며, ch가 닫히면 w를 종료한다.
+func KickOnReceive[T any](w *Watchdog, ch <-chan T) {
+	go func() {
+		for {
+			_, ok := Receive(w.ctx, ch)
+			if !ok {
+				w.Stop()
+				return
+			}
+			w.Kick()
+		}
+	}()
+}
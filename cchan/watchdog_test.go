@@ -0,0 +1,119 @@
+package cchan_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jae2274/goutils/cchan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog(t *testing.T) {
+	timeout := 100 * time.Millisecond
+
+	t.Run("Timeout동안 Kick되지 않으면 한번만 이벤트가 발생하고, Kick되면 다시 감시한다.", func(t *testing.T) {
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: timeout}, nil)
+		defer watchdog.Stop()
+
+		event := <-watchdog.Events()
+		require.Equal(t, cchan.WatchdogTimeout, event.Level)
+		require.Equal(t, 1, event.Timeouts)
+		require.GreaterOrEqual(t, event.Idle, timeout)
+
+		time.Sleep(2 * timeout)
+		assertLength(t, watchdog.Events(), 0)
+
+		watchdog.Kick()
+		time.Sleep(timeout + 50*time.Millisecond)
+		assertLength(t, watchdog.Events(), 1)
+	})
+
+	t.Run("Kick이 계속되면 이벤트가 발생하지 않는다.", func(t *testing.T) {
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{InitTimeout: 2 * timeout, Timeout: timeout}, nil)
+		defer watchdog.Stop()
+
+		time.Sleep(timeout + 50*time.Millisecond) // InitTimeout 이내이다.
+		for i := 0; i < 5; i++ {
+			watchdog.Kick()
+			time.Sleep(timeout / 4)
+		}
+		assertLength(t, watchdog.Events(), 0)
+	})
+
+	t.Run("Repeat이면 연속으로 timeout된 횟수에 따라 Warn, Abort 이벤트가 발생하고, Abort 이후 종료된다.", func(t *testing.T) {
+		mu := sync.Mutex{}
+		var levels []cchan.WatchdogLevel
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: 50 * time.Millisecond, Repeat: true, WarnAfter: 2, AbortAfter: 4}, func(event cchan.WatchdogEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			levels = append(levels, event.Level)
+		})
+
+		select {
+		case <-watchdog.Done():
+		case <-time.After(time.Second):
+			require.Fail(t, "watchdog is not aborted")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []cchan.WatchdogLevel{cchan.WatchdogTimeout, cchan.WatchdogWarn, cchan.WatchdogWarn, cchan.WatchdogAbort}, levels)
+	})
+
+	t.Run("Repeat이 아니면 timeout된 후의 Kick은 횟수를 초기화하지 않으므로, 늦은 Kick이 반복되면 Warn, Abort 이벤트가 발생한다.", func(t *testing.T) {
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: 50 * time.Millisecond, WarnAfter: 2, AbortAfter: 3}, nil)
+		defer watchdog.Stop()
+
+		var levels []cchan.WatchdogLevel
+		for event := range watchdog.Events() {
+			levels = append(levels, event.Level)
+			watchdog.Kick()
+		}
+		require.Equal(t, []cchan.WatchdogLevel{cchan.WatchdogTimeout, cchan.WatchdogWarn, cchan.WatchdogAbort}, levels)
+	})
+
+	t.Run("Repeat이 아니어도 timeout되기 전에 Kick되면 횟수가 초기화된다.", func(t *testing.T) {
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: timeout, WarnAfter: 2}, nil)
+		defer watchdog.Stop()
+
+		require.Equal(t, 1, (<-watchdog.Events()).Timeouts)
+		watchdog.Kick()
+		time.Sleep(timeout / 4)
+		watchdog.Kick() // timeout되기 전의 Kick이다.
+
+		event := <-watchdog.Events()
+		require.Equal(t, 1, event.Timeouts)
+		require.Equal(t, cchan.WatchdogTimeout, event.Level)
+	})
+
+	t.Run("ctx가 종료되면 Events 채널이 닫힌다.", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		watchdog := cchan.NewWatchdog(ctx, cchan.WatchdogConfig{Timeout: time.Second}, nil)
+
+		cancel()
+		_, ok := <-watchdog.Events()
+		require.False(t, ok)
+	})
+
+	t.Run("KickOnReceive는 채널로 데이터가 전달되면 Kick하고, 채널이 닫히면 종료한다.", func(t *testing.T) {
+		watchdog := cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: 2 * timeout}, nil)
+		processedChan := make(chan ProcessedSignal)
+		cchan.KickOnReceive(watchdog, processedChan)
+
+		for i := 0; i < 4; i++ {
+			processedChan <- ProcessedSignal{}
+			time.Sleep(timeout / 2)
+		}
+		assertLength(t, watchdog.Events(), 0)
+
+		close(processedChan)
+		<-watchdog.Done()
+	})
+
+	t.Run("Timeout이 0 이하이면 panic이 발생한다.", func(t *testing.T) {
+		require.Panics(t, func() { cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Repeat: true}, nil) })
+		require.Panics(t, func() { cchan.NewWatchdog(context.Background(), cchan.WatchdogConfig{Timeout: -timeout}, nil) })
+	})
+}